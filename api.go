@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"yagui/utils"
+)
+
+// apiMethods maps the control API's JSON-RPC methods onto App methods so
+// that API clients see exactly what the UI does.
+func (a *App) apiMethods() map[string]utils.ControlMethod {
+	return map[string]utils.ControlMethod{
+		// list {dir?} -> {name: shortcut}
+		"list": func(raw json.RawMessage) (interface{}, error) {
+			var p struct {
+				Dir string `json:"dir"`
+			}
+			if err := utils.DecodeParams(raw, &p); err != nil {
+				return nil, err
+			}
+			return a.GetShortcutsForDir(p.Dir)
+		},
+		// search {query, tag?, dir?} -> [shortcut with name]
+		"search": func(raw json.RawMessage) (interface{}, error) {
+			var p struct {
+				Query string `json:"query"`
+				Tag   string `json:"tag"`
+				Dir   string `json:"dir"`
+			}
+			if err := utils.DecodeParams(raw, &p); err != nil {
+				return nil, err
+			}
+			shortcuts, err := a.GetShortcutsForDir(p.Dir)
+			if err != nil {
+				return nil, err
+			}
+			return utils.SearchShortcuts(shortcuts, p.Query, p.Tag), nil
+		},
+		// variables {name, dir?} -> [placeholder]
+		"variables": func(raw json.RawMessage) (interface{}, error) {
+			var p struct {
+				Name string `json:"name"`
+				Dir  string `json:"dir"`
+			}
+			if err := utils.DecodeParams(raw, &p); err != nil {
+				return nil, err
+			}
+			s, err := utils.GetShortcutForDir(p.Name, p.Dir)
+			if err != nil {
+				return nil, err
+			}
+			return utils.ParsePlaceholders(s.Command)
+		},
+		// render {name, values?, dir?} -> command with built-ins left as written
+		"render": func(raw json.RawMessage) (interface{}, error) {
+			var p struct {
+				Name   string            `json:"name"`
+				Values map[string]string `json:"values"`
+				Dir    string            `json:"dir"`
+			}
+			if err := utils.DecodeParams(raw, &p); err != nil {
+				return nil, err
+			}
+			s, err := utils.GetShortcutForDir(p.Name, p.Dir)
+			if err != nil {
+				return nil, err
+			}
+			return utils.RenderCommand(s.Command, utils.RenderOptions{Values: p.Values})
+		},
+		// run {name, source?, dir?, values?, mode?} -> {mode, id?}; mode is
+		// "terminal" (default) or "inline", and dir defaults to the last-used
+		// directory. source is the shortcut's source as list reports it, so a
+		// project shortcut has to be asked for as one.
+		"run": func(raw json.RawMessage) (interface{}, error) {
+			var p struct {
+				Name   string            `json:"name"`
+				Source string            `json:"source"`
+				Dir    string            `json:"dir"`
+				Values map[string]string `json:"values"`
+				Mode   string            `json:"mode"`
+			}
+			if err := utils.DecodeParams(raw, &p); err != nil {
+				return nil, err
+			}
+			if p.Dir == "" {
+				cfg, _ := utils.GetConfig()
+				p.Dir = cfg.DefaultDir
+			}
+			if p.Dir == "" {
+				return nil, utils.ControlParamsError{Err: errors.New("dir is required")}
+			}
+			result := struct {
+				Mode string `json:"mode"`
+				ID   string `json:"id,omitempty"`
+			}{Mode: p.Mode}
+			var err error
+			switch p.Mode {
+			case "", "terminal":
+				result.Mode = "terminal"
+				err = a.ApplyShortcut(p.Name, p.Source, p.Dir, p.Values)
+			case "inline":
+				result.ID, err = a.RunShortcut("", p.Name, p.Source, p.Dir, p.Values)
+			default:
+				return nil, utils.ControlParamsError{Err: errors.New(`mode must be "terminal" or "inline"`)}
+			}
+			if err != nil {
+				return nil, err
+			}
+			// Run counts and history changed behind the UI's back.
+			a.emit("shortcuts:changed")
+			a.emit("history:changed")
+			return result, nil
+		},
+		// status {id} -> {id, running, exitCode?, error?, output}; output is
+		// the last lines the run printed.
+		"status": func(raw json.RawMessage) (interface{}, error) {
+			var p struct {
+				ID string `json:"id"`
+			}
+			if err := utils.DecodeParams(raw, &p); err != nil {
+				return nil, err
+			}
+			if p.ID == "" {
+				return nil, utils.ControlParamsError{Err: errors.New("id is required")}
+			}
+			return a.runStatus(p.ID)
+		},
+		// history {limit?} -> [entry], newest first
+		"history": func(raw json.RawMessage) (interface{}, error) {
+			var p struct {
+				Limit int `json:"limit"`
+			}
+			if err := utils.DecodeParams(raw, &p); err != nil {
+				return nil, err
+			}
+			entries, err := a.GetRunHistory()
+			if err != nil {
+				return nil, err
+			}
+			if p.Limit > 0 && len(entries) > p.Limit {
+				entries = entries[:p.Limit]
+			}
+			return entries, nil
+		},
+	}
+}
+
+// RunStatus is what is known about a run: its output so far while it is
+// running, and once it has finished, the outcome kept in its history entry.
+type RunStatus struct {
+	ID       string   `json:"id"`
+	Running  bool     `json:"running"`
+	ExitCode *int     `json:"exitCode,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Output   []string `json:"output"`
+}
+
+// runStatus looks run id up among the running processes, then in history.
+func (a *App) runStatus(id string) (RunStatus, error) {
+	status := RunStatus{ID: id, Output: []string{}}
+	a.outputsMu.Lock()
+	tail, live := a.outputs[id]
+	a.outputsMu.Unlock()
+	if live {
+		status.Running = true
+		status.Output = tail.Lines()
+		return status, nil
+	}
+	for _, p := range utils.ListRunningProcesses() {
+		if p.ID == id {
+			status.Running = true
+			return status, nil
+		}
+	}
+	entries, err := utils.GetRunHistory()
+	if err != nil {
+		return status, err
+	}
+	for _, e := range entries {
+		if e.ID != id {
+			continue
+		}
+		status.ExitCode, status.Error = e.ExitCode, e.Error
+		if e.OutputTail != nil {
+			status.Output = e.OutputTail
+		}
+		return status, nil
+	}
+	return status, fmt.Errorf("run %q not found", id)
+}
+
+// startAPI starts the control API unless it is already running.
+func (a *App) startAPI() error {
+	a.apiMu.Lock()
+	defer a.apiMu.Unlock()
+	if a.api != nil {
+		return nil
+	}
+	s, err := utils.StartControlServer(a.apiMethods())
+	if err != nil {
+		return err
+	}
+	a.api = s
+	return nil
+}
+
+func (a *App) stopAPI() {
+	a.apiMu.Lock()
+	defer a.apiMu.Unlock()
+	if a.api != nil {
+		a.api.Close()
+		a.api = nil
+	}
+}
+
+// SetEnableAPI turns the control API on or off and saves the choice.
+func (a *App) SetEnableAPI(enabled bool) error {
+	if enabled {
+		if err := a.startAPI(); err != nil {
+			return err
+		}
+	} else {
+		a.stopAPI()
+	}
+	return utils.SetEnableAPI(enabled)
+}
+
+// GetAPIInfo reports where the control API listens; Address is empty while
+// it is off.
+func (a *App) GetAPIInfo() utils.APIInfo {
+	a.apiMu.Lock()
+	defer a.apiMu.Unlock()
+	if a.api == nil {
+		return utils.APIInfo{}
+	}
+	return a.api.Info()
+}
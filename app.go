@@ -2,13 +2,28 @@
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"time"
 	"yagui/utils"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 type App struct {
-	ctx context.Context
+	ctx     context.Context
+	watcher *utils.DataWatcher
+	api     *utils.ControlServer
+	apiMu   sync.Mutex
+
+	instance *utils.Instance
+	launchMu sync.Mutex
+	launches []utils.LaunchRequest
+
+	// outputs holds the output of app-owned runs until they have finished.
+	outputsMu sync.Mutex
+	outputs   map[string]*utils.OutputTail
 }
 
 func NewApp() *App {
@@ -17,6 +32,36 @@ func NewApp() *App {
 
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	_ = utils.PurgeTrash()
+	// Live reload is best-effort: without it the UI still works, it just
+	// will not notice edits made by the CLI until it re-fetches.
+	if w, err := utils.WatchDataDir(func(event string) { a.emit(event) }); err == nil {
+		a.watcher = w
+	}
+	if cfg, err := utils.GetConfig(); err == nil && cfg.EnableAPI {
+		_ = a.startAPI()
+	}
+	if a.instance != nil {
+		a.instance.Serve(a.onSecondInstance)
+	}
+}
+
+func (a *App) shutdown(ctx context.Context) {
+	if a.watcher != nil {
+		a.watcher.Close()
+	}
+	a.stopAPI()
+	if a.instance != nil {
+		a.instance.Close()
+	}
+	utils.CloseAllTerminalSessions()
+}
+
+// emit sends a Wails event to the frontend once the app has started.
+func (a *App) emit(event string, data ...interface{}) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, event, data...)
+	}
 }
 
 func (a *App) GetVersion() string {
@@ -29,6 +74,28 @@ func (a *App) GetShortcuts() (map[string]utils.ShortcutData, error) {
 	return utils.GetShortcuts()
 }
 
+// GetShortcutsForDir returns the library with the project shortcuts of dir's
+// .ya.json files layered over it.
+func (a *App) GetShortcutsForDir(dir string) (map[string]utils.ShortcutData, error) {
+	return utils.GetShortcutsForDir(dir)
+}
+
+// GetUntrustedProjectFiles lists the .ya.json files of dir that are kept
+// from replacing library shortcuts until the user trusts them.
+func (a *App) GetUntrustedProjectFiles(dir string) ([]utils.ProjectShadow, error) {
+	return utils.UntrustedProjectFiles(dir)
+}
+
+// TrustProjectFile lets the .ya.json at path replace library shortcuts
+// while it keeps the contents whose sum is given.
+func (a *App) TrustProjectFile(path, sum string) error {
+	if err := utils.TrustProjectFile(path, sum); err != nil {
+		return err
+	}
+	a.emit("shortcuts:changed")
+	return nil
+}
+
 // AddShortcut creates or replaces a shortcut. tags is comma-separated.
 func (a *App) AddShortcut(name, command, description, tags string) (map[string]utils.ShortcutData, error) {
 	if err := utils.AddShortcut(name, command, description, tags); err != nil {
@@ -61,12 +128,190 @@ func (a *App) ExportShortcuts() error {
 
 func (a *App) ImportShortcuts() error {
 	err := utils.ImportShortcuts(a.ctx)
-	if a.ctx != nil {
-		runtime.EventsEmit(a.ctx, "shortcuts:changed")
-	}
+	a.emit("shortcuts:changed")
 	return err
 }
 
+// ExportShell exports the library as shell aliases/functions ("bash",
+// "fish", "powershell") or a folder of scripts ("scripts") and reports the
+// path written ("" if cancelled) and any scripts left out.
+func (a *App) ExportShell(format string) (utils.ShellExportResult, error) {
+	return utils.ExportShell(a.ctx, format)
+}
+
+// ExportBundle writes shortcuts, metadata, config and optionally run history
+// to a single file and returns its path ("" if cancelled).
+func (a *App) ExportBundle(includeHistory bool) (string, error) {
+	return utils.ExportBundle(a.ctx, includeHistory)
+}
+
+// ImportBundle restores a file written by ExportBundle.
+func (a *App) ImportBundle(path string) (utils.BundleReport, error) {
+	report, err := utils.ImportBundle(path)
+	a.emit("shortcuts:changed")
+	a.emit("config:changed")
+	a.emit("history:changed")
+	return report, err
+}
+
+// SelectImportFile opens a file dialog; "" means the user cancelled.
+func (a *App) SelectImportFile() (string, error) {
+	return utils.SelectImportFile(a.ctx)
+}
+
+// PreviewImport compares a shortcuts file with the library without changing it.
+func (a *App) PreviewImport(path string) (utils.ImportPlan, error) {
+	return utils.PreviewImport(path)
+}
+
+// ShellProfiles lists the shell profile files found in the home directory.
+func (a *App) ShellProfiles() []string {
+	return utils.ShellProfiles()
+}
+
+// ApplyImport imports a previewed plan once each conflict has been resolved.
+func (a *App) ApplyImport(plan utils.ImportPlan) (utils.ImportResult, error) {
+	result, err := utils.ApplyImport(plan)
+	if err != nil {
+		return result, err
+	}
+	a.emit("shortcuts:changed")
+	return result, nil
+}
+
+// Undo reverts the most recent change to the library and returns it.
+func (a *App) Undo() (utils.JournalEntry, error) {
+	entry, err := utils.Undo()
+	if err != nil {
+		return entry, err
+	}
+	a.emit("shortcuts:changed")
+	return entry, nil
+}
+
+// Redo reapplies the most recently undone change and returns it.
+func (a *App) Redo() (utils.JournalEntry, error) {
+	entry, err := utils.Redo()
+	if err != nil {
+		return entry, err
+	}
+	a.emit("shortcuts:changed")
+	return entry, nil
+}
+
+// GetRecentChanges returns what Undo and Redo would act on, newest first.
+func (a *App) GetRecentChanges() (utils.RecentChanges, error) {
+	return utils.GetRecentChanges()
+}
+
+//  Trash
+
+// ListTrash returns deleted shortcuts, most recently deleted first.
+func (a *App) ListTrash() ([]utils.TrashEntry, error) {
+	return utils.ListTrash()
+}
+
+// RestoreFromTrash restores a deleted shortcut and returns its new name,
+// which differs from name if that has been taken since.
+func (a *App) RestoreFromTrash(name string) (string, error) {
+	restored, err := utils.RestoreFromTrash(name)
+	if err != nil {
+		return "", err
+	}
+	a.emit("shortcuts:changed")
+	return restored, nil
+}
+
+func (a *App) EmptyTrash() error {
+	return utils.EmptyTrash()
+}
+
+//  Backups
+
+// ListBackups returns the automatic snapshots of the library, newest first.
+func (a *App) ListBackups() ([]utils.BackupInfo, error) {
+	return utils.ListBackups()
+}
+
+// PreviewBackup shows what restoring backup id would change.
+func (a *App) PreviewBackup(id string) (utils.BackupPreview, error) {
+	return utils.PreviewBackup(id)
+}
+
+// RestoreBackup replaces the library with backup id.
+func (a *App) RestoreBackup(id string) error {
+	if err := utils.RestoreBackup(id); err != nil {
+		return err
+	}
+	a.emit("shortcuts:changed")
+	return nil
+}
+
+//  Variables
+
+// ParseShortcutVariables returns the typed placeholder schema of a shortcut's command.
+func (a *App) ParseShortcutVariables(name string) ([]utils.Placeholder, error) {
+	s, err := utils.GetShortcut(name)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ParsePlaceholders(s.Command)
+}
+
+// ParseCommandVariables returns the typed placeholder schema of command.
+func (a *App) ParseCommandVariables(command string) ([]utils.Placeholder, error) {
+	return utils.ParsePlaceholders(command)
+}
+
+// GetVariableHistory returns the recently used values of each of a
+// shortcut's placeholders, newest first.
+func (a *App) GetVariableHistory(name string) (map[string][]string, error) {
+	return utils.GetVariableHistory(name)
+}
+
+// RenderCommand substitutes values into a shortcut's placeholders after
+// validating them against the schema. Built-in variables are left as written
+// since they are only resolved once a directory has been chosen.
+func (a *App) RenderCommand(name string, values map[string]string) (string, error) {
+	s, err := utils.GetShortcut(name)
+	if err != nil {
+		return "", err
+	}
+	return utils.RenderCommand(s.Command, utils.RenderOptions{Values: values})
+}
+
+// renderForRun renders shortcutName's command, as seen from dirPath, with
+// values quoted for shell and resolves built-in variables against dirPath.
+// source is where the caller expects the shortcut to come from, as for
+// utils.GetShortcutFromSource.
+func (a *App) renderForRun(shortcutName, source, dirPath, shell string, values map[string]string) (string, error) {
+	s, err := utils.GetShortcutFromSource(shortcutName, source, dirPath)
+	if err != nil {
+		return "", err
+	}
+	return a.renderCommandForRun(s.Command, dirPath, shell, values)
+}
+
+// renderCommandForRun is renderForRun for a command that is not a saved
+// shortcut.
+func (a *App) renderCommandForRun(command, dirPath, shell string, values map[string]string) (string, error) {
+	return utils.RenderCommand(command, utils.RenderOptions{
+		Values: values,
+		Builtins: &utils.BuiltinContext{
+			Dir:       dirPath,
+			Clipboard: a.clipboardText,
+		},
+		Shell: shell,
+	})
+}
+
+func (a *App) clipboardText() (string, error) {
+	if a.ctx == nil {
+		return "", errors.New("clipboard is not available")
+	}
+	return runtime.ClipboardGetText(a.ctx)
+}
+
 //  Terminal & directory
 
 // SelectDirectory opens the native directory picker and returns the chosen path.
@@ -79,16 +324,229 @@ func (a *App) SelectDirectory() (string, error) {
 	return runtime.OpenDirectoryDialog(a.ctx, opts)
 }
 
-// ApplyShortcut launches shortcutName's command in dirPath, records history.
-func (a *App) ApplyShortcut(shortcutName, command, dirPath string) bool {
+// DiscoverProjectTasks lists the tasks defined by the build files in dir.
+func (a *App) DiscoverProjectTasks(dir string) ([]utils.ProjectTask, error) {
+	return utils.DiscoverProjectTasks(dir)
+}
+
+// PromoteProjectTask saves a task discovered in dir to the library as name.
+func (a *App) PromoteProjectTask(dir, taskName, name string) error {
+	if err := utils.PromoteProjectTask(dir, taskName, name); err != nil {
+		return err
+	}
+	a.emit("shortcuts:changed")
+	return nil
+}
+
+// ApplyShortcut renders shortcutName's command with values, launches it in a
+// terminal in dirPath and records history. source is the .ya.json the
+// shortcut was listed from, or "" for the library; the run is refused if
+// shortcutName comes from somewhere else in dirPath.
+func (a *App) ApplyShortcut(shortcutName, source, dirPath string, values map[string]string) error {
+	s, err := utils.GetShortcutFromSource(shortcutName, source, dirPath)
+	if err != nil {
+		return err
+	}
+	return a.applyCommand(shortcutName, s.Command, s.Source, dirPath, values)
+}
+
+// ApplyProjectTask runs a task found by DiscoverProjectTasks in dirPath in a
+// terminal, like ApplyShortcut.
+func (a *App) ApplyProjectTask(dirPath, taskName string, values map[string]string) error {
+	t, err := utils.FindProjectTask(dirPath, taskName)
+	if err != nil {
+		return err
+	}
+	return a.applyCommand(taskName, t.Command, filepath.Join(dirPath, t.Source), dirPath, values)
+}
+
+// applyCommand renders command, launches it in a terminal in dirPath and
+// records history under name. source is the file the command came from when
+// it is not in the library.
+func (a *App) applyCommand(name, command, source, dirPath string, values map[string]string) error {
 	cfg, _ := utils.GetConfig()
-	if err := utils.LaunchInTerminal(command, dirPath, cfg.PreferredTerminal); err != nil {
-		return false
+	command, err := a.renderCommandForRun(command, dirPath, utils.TerminalShell(cfg.PreferredTerminal), values)
+	if err != nil {
+		return err
+	}
+	id := utils.NewRunID()
+	terminal, err := utils.LaunchInTerminal(id, name, command, dirPath, cfg.PreferredTerminal, func(exit utils.RunExit) {
+		a.emitExited(id, name, exit)
+	})
+	if err != nil {
+		return err
+	}
+	a.recordRun(utils.RunHistoryEntry{
+		ID:           id,
+		ShortcutName: name,
+		Command:      command,
+		Directory:    dirPath,
+		Mode:         "terminal",
+		Source:       source,
+		Terminal:     terminal,
+	}, values)
+	return nil
+}
+
+// NewRunID returns an ID for RunShortcut or StartTerminalSession, so the
+// frontend can subscribe to the run's events before starting it.
+func (a *App) NewRunID() string {
+	return utils.NewRunID()
+}
+
+// RunShortcut renders shortcutName's command with values and executes it in
+// dirPath as a child process of the app instead of a terminal window. Each
+// output line is emitted as "run:<id>:output" and the exit code as
+// "run:<id>:exit". id may be empty, in which case one is generated; either
+// way the run's ID is returned. source is checked as by ApplyShortcut.
+func (a *App) RunShortcut(id, shortcutName, source, dirPath string, values map[string]string) (string, error) {
+	id, err := utils.ResolveRunID(id)
+	if err != nil {
+		return "", err
+	}
+	defer utils.ReleaseRunID(id)
+	command, err := a.renderForRun(shortcutName, source, dirPath, utils.DefaultShell(), values)
+	if err != nil {
+		return "", err
+	}
+	tail := &utils.OutputTail{}
+	started := time.Now()
+	recorded := make(chan struct{})
+	err = utils.StartRun(id, shortcutName, command, dirPath, utils.RunHandlers{
+		OnOutput: func(out utils.RunOutput) {
+			tail.AddLine(out.Line)
+			a.emit("run:"+id+":output", out)
+		},
+		OnExit: func(exit utils.RunExit) {
+			<-recorded
+			a.finishRun(id, exit, started, tail)
+			a.emit("run:"+id+":exit", exit)
+			a.emitExited(id, shortcutName, exit)
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	a.recordRun(utils.RunHistoryEntry{
+		ID:           id,
+		ShortcutName: shortcutName,
+		Command:      command,
+		Directory:    dirPath,
+		Mode:         "inline",
+		Source:       source,
+	}, values)
+	a.trackOutput(id, tail)
+	close(recorded)
+	return id, nil
+}
+
+// StartTerminalSession renders shortcutName's command with values and runs it
+// in dirPath under a pseudo-terminal of cols x rows for interactive use. Raw
+// output bytes are emitted as "pty:<id>:output" (base64 in the event payload)
+// and the exit code as "pty:<id>:exit". As with RunShortcut, id may be empty
+// and source is checked.
+func (a *App) StartTerminalSession(id, shortcutName, source, dirPath string, values map[string]string, cols, rows int) (string, error) {
+	id, err := utils.ResolveRunID(id)
+	if err != nil {
+		return "", err
+	}
+	defer utils.ReleaseRunID(id)
+	command, err := a.renderForRun(shortcutName, source, dirPath, utils.ShellPOSIX, values)
+	if err != nil {
+		return "", err
+	}
+	tail := &utils.OutputTail{}
+	started := time.Now()
+	recorded := make(chan struct{})
+	err = utils.StartTerminalSession(id, shortcutName, command, dirPath, uint16(cols), uint16(rows), utils.SessionHandlers{
+		OnOutput: func(data []byte) {
+			_, _ = tail.Write(data)
+			a.emit("pty:"+id+":output", data)
+		},
+		OnExit: func(exit utils.RunExit) {
+			<-recorded
+			a.finishRun(id, exit, started, tail)
+			a.emit("pty:"+id+":exit", exit)
+			a.emitExited(id, shortcutName, exit)
+		},
+	})
+	if err != nil {
+		return "", err
 	}
-	_ = utils.UpdateDefaultDir(dirPath)
-	_ = utils.AddRunHistoryEntry(shortcutName, command, dirPath)
-	_ = utils.IncrementRunCount(shortcutName)
-	return true
+	a.recordRun(utils.RunHistoryEntry{
+		ID:           id,
+		ShortcutName: shortcutName,
+		Command:      command,
+		Directory:    dirPath,
+		Mode:         "pty",
+		Source:       source,
+	}, values)
+	a.trackOutput(id, tail)
+	close(recorded)
+	return id, nil
+}
+
+// WriteTerminalSession sends keyboard input to a terminal session.
+func (a *App) WriteTerminalSession(id, data string) error {
+	return utils.WriteTerminalSession(id, data)
+}
+
+// ResizeTerminalSession resizes a terminal session to cols x rows.
+func (a *App) ResizeTerminalSession(id string, cols, rows int) error {
+	return utils.ResizeTerminalSession(id, uint16(cols), uint16(rows))
+}
+
+// CloseTerminalSession terminates a terminal session.
+func (a *App) CloseTerminalSession(id string) error {
+	return utils.CloseTerminalSession(id)
+}
+
+// ListRunningShortcuts returns every launched shortcut that is still running.
+func (a *App) ListRunningShortcuts() []utils.RunningProcess {
+	return utils.ListRunningProcesses()
+}
+
+// StopRun gracefully stops run id and its child processes, force-killing
+// them if they have not exited within a few seconds.
+func (a *App) StopRun(id string) error {
+	return utils.StopRun(id)
+}
+
+// emitExited broadcasts "run:exited" for any tracked run, whatever its mode.
+func (a *App) emitExited(id, shortcutName string, exit utils.RunExit) {
+	a.emit("run:exited", utils.ProcessExit{ID: id, ShortcutName: shortcutName, RunExit: exit})
+}
+
+// recordRun persists the side effects of a successful launch: last-used
+// directory, a history entry, the shortcut's run count and the variable
+// values it was run with. Usage stats are only kept for library shortcuts.
+func (a *App) recordRun(entry utils.RunHistoryEntry, values map[string]string) {
+	_ = utils.UpdateDefaultDir(entry.Directory)
+	_ = utils.AddRunHistoryEntry(entry)
+	if entry.Source != "" {
+		return
+	}
+	_ = utils.IncrementRunCount(entry.ShortcutName)
+	_ = utils.RecordVariableValues(entry.ShortcutName, values)
+}
+
+// finishRun stores the outcome of an app-owned run in its history entry.
+func (a *App) finishRun(id string, exit utils.RunExit, started time.Time, tail *utils.OutputTail) {
+	if err := utils.FinishRunHistoryEntry(id, exit, time.Since(started), tail.Lines()); err == nil {
+		a.emit("history:changed")
+	}
+	a.outputsMu.Lock()
+	delete(a.outputs, id)
+	a.outputsMu.Unlock()
+}
+
+func (a *App) trackOutput(id string, tail *utils.OutputTail) {
+	a.outputsMu.Lock()
+	defer a.outputsMu.Unlock()
+	if a.outputs == nil {
+		a.outputs = map[string]*utils.OutputTail{}
+	}
+	a.outputs[id] = tail
 }
 
 func (a *App) CliExists(cmd string) bool {
@@ -105,6 +563,31 @@ func (a *App) SetPreferredTerminal(terminal string) error {
 	return utils.SetPreferredTerminal(terminal)
 }
 
+// SetTrashRetentionDays sets how long deleted shortcuts are kept (0 = 30 days).
+func (a *App) SetTrashRetentionDays(days int) error {
+	return utils.SetTrashRetentionDays(days)
+}
+
+// GetTerminalLaunchers lists the built-in terminals for this OS and the
+// user's custom ones.
+func (a *App) GetTerminalLaunchers() ([]utils.TerminalLauncher, error) {
+	return utils.TerminalLaunchers()
+}
+
+// SaveCustomTerminal adds or replaces a custom terminal. template is the
+// command line to start it with, using {shell}, {dir} and {command}.
+func (a *App) SaveCustomTerminal(name, label, template, shell string) error {
+	args, err := utils.ParseTerminalTemplate(template)
+	if err != nil {
+		return err
+	}
+	return utils.SaveCustomTerminal(utils.TerminalLauncher{Name: name, Label: label, Args: args, Shell: shell})
+}
+
+func (a *App) RemoveCustomTerminal(name string) error {
+	return utils.RemoveCustomTerminal(name)
+}
+
 func (a *App) SetStartOnBoot(enabled bool) error {
 	return utils.SetStartOnBoot(enabled)
 }
@@ -113,6 +596,15 @@ func (a *App) GetStartOnBoot() bool {
 	return utils.GetStartOnBoot()
 }
 
+// SetURLHandler registers or unregisters Ya GUI for yagui:// links.
+func (a *App) SetURLHandler(enabled bool) error {
+	return utils.SetURLHandler(enabled)
+}
+
+func (a *App) GetURLHandler() bool {
+	return utils.GetURLHandler()
+}
+
 func (a *App) AddSavedDirectory(name, path string) error {
 	return utils.AddSavedDirectory(name, path)
 }
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"yagui/utils"
+)
+
+// cliCommand is a headless subcommand: `yagui <name> [args]`.
+type cliCommand struct {
+	usage string
+	help  string
+	run   func(args []string) error
+}
+
+var cliCommands map[string]cliCommand
+
+func init() {
+	cliCommands = map[string]cliCommand{
+		"list":    {"list [--dir DIR] [--tag TAG]", "List shortcuts as JSON, including project shortcuts for DIR", cliList},
+		"show":    {"show NAME [--dir DIR]", "Show one shortcut and its placeholders", cliShow},
+		"run":     {"run NAME [--dir DIR] [--var NAME=VALUE]... [--dry-run]", "Run a shortcut; its output goes to stderr, the result to stdout", cliRun},
+		"tasks":   {"tasks [--dir DIR]", "List the project tasks found in DIR", cliTasks},
+		"history": {"history [--limit N]", "Print the run history, newest first", cliHistory},
+		"export":  {"export [--format json|bundle|bash|fish|powershell] [--history] [-o FILE]", "Export the library to FILE or stdout", cliExport},
+		"import":  {"import FILE [--dry-run]", "Merge shortcuts from FILE, taking the incoming side of conflicts", cliImport},
+		"help":    {"help", "Show this help", func([]string) error { cliUsage(os.Stdout); return nil }},
+		"version": {"version", "Print the version", func([]string) error { return printJSON(map[string]string{"version": AppVersion}) }},
+	}
+}
+
+// errUsage marks a command-line mistake; the message has already been printed.
+var errUsage = errors.New("usage")
+
+// exitError makes runCLI exit with code without printing anything more.
+type exitError struct{ code int }
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+
+// runCLI runs the subcommand named by args[0] without starting the GUI. ok
+// is false when args do not name a subcommand, so that anything else (for
+// example flags added by the OS launcher) still opens the window.
+func runCLI(args []string) (code int, ok bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	name := strings.TrimLeft(args[0], "-")
+	if args[0] == "-h" {
+		name = "help"
+	}
+	cmd, ok := cliCommands[name]
+	if !ok || (strings.HasPrefix(args[0], "-") && name != "help" && name != "version") {
+		return 0, false
+	}
+	attachConsole()
+	err := cmd.run(args[1:])
+	var exit exitError
+	switch {
+	case err == nil:
+		return 0, true
+	case errors.As(err, &exit):
+		return exit.code, true
+	case errors.Is(err, errUsage):
+		return 2, true
+	default:
+		fmt.Fprintln(os.Stderr, "yagui:", err)
+		return 1, true
+	}
+}
+
+func cliUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: yagui [command]")
+	fmt.Fprintln(w, "\nWithout a command the app window opens. Commands print JSON:")
+	names := make([]string, 0, len(cliCommands))
+	for name := range cliCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c := cliCommands[name]
+		fmt.Fprintf(w, "\n  yagui %s\n      %s\n", c.usage, c.help)
+	}
+}
+
+// newFlags returns a flag set for subcommand name that reports errors as
+// errUsage.
+func newFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: yagui %s\n", cliCommands[name].usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args with fs, allowing flags after positional arguments
+// (`yagui run build --dir .`), and returns the positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string, nargs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) != nargs {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// varsFlag collects repeated --var NAME=VALUE flags.
+type varsFlag map[string]string
+
+func (v varsFlag) String() string { return "" }
+
+func (v varsFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected NAME=VALUE, got %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// absDir resolves dir for the commands that take --dir.
+func absDir(dir string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+	return filepath.Abs(dir)
+}
+
+func cliList(args []string) error {
+	fs := newFlags("list")
+	dir := fs.String("dir", "", "also list the project shortcuts of `DIR`")
+	tag := fs.String("tag", "", "only list shortcuts tagged `TAG`")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	d, err := absDir(*dir)
+	if err != nil {
+		return err
+	}
+	shortcuts, err := utils.GetShortcutsForDir(d)
+	if err != nil {
+		return err
+	}
+	if *tag != "" {
+		for name, s := range shortcuts {
+			if !hasTag(s.Tags, *tag) {
+				delete(shortcuts, name)
+			}
+		}
+	}
+	return printJSON(shortcuts)
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func cliShow(args []string) error {
+	fs := newFlags("show")
+	dir := fs.String("dir", "", "resolve project shortcuts from `DIR`")
+	pos, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	d, err := absDir(*dir)
+	if err != nil {
+		return err
+	}
+	s, err := utils.GetShortcutForDir(pos[0], d)
+	if err != nil {
+		return err
+	}
+	vars, err := utils.ParsePlaceholders(s.Command)
+	if err != nil {
+		return err
+	}
+	return printJSON(struct {
+		Name string `json:"name"`
+		utils.ShortcutData
+		Variables []utils.Placeholder `json:"variables"`
+	}{pos[0], s, vars})
+}
+
+// cliRunResult is what `yagui run` prints once the command has finished.
+type cliRunResult struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name"`
+	Command    string `json:"command"`
+	Directory  string `json:"directory"`
+	ExitCode   *int   `json:"exitCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+func cliRun(args []string) error {
+	fs := newFlags("run")
+	dir := fs.String("dir", ".", "run in `DIR`")
+	dryRun := fs.Bool("dry-run", false, "print the rendered command without running it")
+	vars := varsFlag{}
+	fs.Var(vars, "var", "set placeholder `NAME=VALUE` (repeatable)")
+	pos, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	name := pos[0]
+	d, err := absDir(*dir)
+	if err != nil {
+		return err
+	}
+	s, err := utils.GetShortcutForDir(name, d)
+	if err != nil {
+		return err
+	}
+	command, err := utils.RenderCommand(s.Command, utils.RenderOptions{
+		Values:   vars,
+		Builtins: &utils.BuiltinContext{Dir: d},
+		Shell:    utils.DefaultShell(),
+	})
+	if err != nil {
+		return err
+	}
+	result := cliRunResult{Name: name, Command: command, Directory: d}
+	if *dryRun {
+		return printJSON(result)
+	}
+
+	result.ID = utils.NewRunID()
+	tail := &utils.OutputTail{}
+	done := make(chan utils.RunExit, 1)
+	started := time.Now()
+	err = utils.StartRun(result.ID, name, command, d, utils.RunHandlers{
+		OnOutput: func(out utils.RunOutput) {
+			tail.AddLine(out.Line)
+			fmt.Fprintln(os.Stderr, out.Line)
+		},
+		OnExit: func(exit utils.RunExit) { done <- exit },
+	})
+	if err != nil {
+		return err
+	}
+	_ = utils.AddRunHistoryEntry(utils.RunHistoryEntry{
+		ID:           result.ID,
+		ShortcutName: name,
+		Command:      command,
+		Directory:    d,
+		Mode:         "cli",
+		Source:       s.Source,
+	})
+	if s.Source == "" {
+		_ = utils.IncrementRunCount(name)
+		_ = utils.RecordVariableValues(name, vars)
+	}
+
+	// The child runs in its own process group, so pass Ctrl+C on to it.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	var exit utils.RunExit
+	for waiting := true; waiting; {
+		select {
+		case <-interrupt:
+			_ = utils.StopRun(result.ID)
+		case exit = <-done:
+			waiting = false
+		}
+	}
+	duration := time.Since(started)
+	_ = utils.FinishRunHistoryEntry(result.ID, exit, duration, tail.Lines())
+
+	result.ExitCode = &exit.ExitCode
+	result.Error = exit.Error
+	result.DurationMs = duration.Milliseconds()
+	if err := printJSON(result); err != nil {
+		return err
+	}
+	switch {
+	case exit.ExitCode > 0:
+		return exitError{exit.ExitCode}
+	case exit.ExitCode < 0:
+		return exitError{1}
+	}
+	return nil
+}
+
+func cliTasks(args []string) error {
+	fs := newFlags("tasks")
+	dir := fs.String("dir", ".", "scan `DIR`")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	d, err := absDir(*dir)
+	if err != nil {
+		return err
+	}
+	tasks, err := utils.DiscoverProjectTasks(d)
+	if err != nil {
+		return err
+	}
+	return printJSON(tasks)
+}
+
+func cliHistory(args []string) error {
+	fs := newFlags("history")
+	limit := fs.Int("limit", 0, "print at most `N` entries")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	entries, err := utils.GetRunHistory()
+	if err != nil {
+		return err
+	}
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[:*limit]
+	}
+	return printJSON(entries)
+}
+
+func cliExport(args []string) error {
+	fs := newFlags("export")
+	format := fs.String("format", "json", "`FORMAT`: json (CLI shortcuts file), bundle (full backup), bash, fish or powershell")
+	history := fs.Bool("history", false, "include run history in a bundle")
+	out := fs.String("o", "", "write to `FILE` instead of stdout")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	var data []byte
+	switch *format {
+	case "json":
+		shortcuts, err := utils.GetShortcuts()
+		if err != nil {
+			return err
+		}
+		cmds := make(map[string]string, len(shortcuts))
+		for name, s := range shortcuts {
+			cmds[name] = s.Command
+		}
+		if data, err = json.MarshalIndent(cmds, "", "  "); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	case "bundle":
+		var err error
+		if data, err = utils.MarshalBundle(*history); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	default:
+		shortcuts, err := utils.GetShortcuts()
+		if err != nil {
+			return err
+		}
+		text, err := utils.RenderShellExport(*format, shortcuts)
+		if err != nil {
+			return err
+		}
+		data = []byte(text)
+	}
+	if *out == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0644)
+}
+
+func cliImport(args []string) error {
+	fs := newFlags("import")
+	dryRun := fs.Bool("dry-run", false, "print the import plan without changing anything")
+	pos, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	plan, err := utils.PreviewImport(pos[0])
+	if err != nil {
+		return err
+	}
+	for i := range plan.Items {
+		if plan.Items[i].Status != utils.ImportIdentical {
+			plan.Items[i].Action = utils.ImportTheirs
+		}
+	}
+	if *dryRun {
+		return printJSON(plan)
+	}
+	result, err := utils.ApplyImport(plan)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
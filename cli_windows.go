@@ -0,0 +1,27 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// attachConsole connects a release build, which is a GUI-subsystem binary
+// without a console of its own, to the console it was started from so that
+// subcommands can print. Redirected output already has valid handles.
+func attachConsole() {
+	if h, err := windows.GetStdHandle(windows.STD_OUTPUT_HANDLE); err == nil && h != 0 && h != windows.InvalidHandle {
+		return
+	}
+	const attachParentProcess = ^uintptr(0) // (DWORD)-1
+	attach := windows.NewLazySystemDLL("kernel32.dll").NewProc("AttachConsole")
+	if r, _, _ := attach.Call(attachParentProcess); r == 0 {
+		return
+	}
+	if f, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout = f
+		os.Stderr = f
+	}
+}
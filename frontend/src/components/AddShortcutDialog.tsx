@@ -8,6 +8,7 @@ import {
     AlertDialogAction,
 } from "@/components/ui/alert-dialog"
 import { Input } from "@/components/ui/input"
+import BuiltinsHint from "@/components/BuiltinsHint"
 
 interface Props {
     open: boolean
@@ -71,6 +72,7 @@ export default function AddShortcutDialog({ open, onAdd, onClose }: Props) {
                             className="font-mono"
                             onKeyDown={(e) => e.key === "Enter" && handleAdd()}
                         />
+                        <BuiltinsHint />
                     </div>
                     <div>
                         <label className="mb-1.5 block text-[13px] font-medium text-fg">
@@ -0,0 +1,15 @@
+// BuiltinsHint lists the built-in variables under a command field. They
+// used to be called {cwd}, {home}, {date}, {time} and {clipboard}; those
+// names are now ordinary placeholders that ask for a value.
+export default function BuiltinsHint() {
+    return (
+        <p className="mt-1.5 text-[11px] leading-relaxed text-fg-faint">
+            Built-ins:{" "}
+            <span className="mono-cell text-fg-muted">
+                {"{ya.cwd} {ya.home} {ya.date} {ya.time} {ya.clipboard} {git.branch} {git.root} {env.NAME}"}
+            </span>
+            . Older <span className="mono-cell">{"{cwd}"}</span>, <span className="mono-cell">{"{date}"}</span> and the like now ask for a value; add{" "}
+            <span className="mono-cell">ya.</span> to keep them automatic.
+        </p>
+    )
+}
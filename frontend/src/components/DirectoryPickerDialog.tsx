@@ -1,4 +1,4 @@
-import { useState } from "react"
+import { useEffect, useState } from "react"
 import {
     AlertDialog,
     AlertDialogContent,
@@ -14,14 +14,21 @@ import { SelectDirectory } from "../../wailsjs/go/main/App"
 
 interface Props {
     open: boolean
+    initialPath?: string
+    shortcutName?: string
+    source?: string     // .ya.json the shortcut comes from; absent for the library
     savedDirectories: SavedDir[]
     onConfirm: (dirPath: string) => void
     onCancel: () => void
 }
 
-export default function DirectoryPickerDialog({ open, savedDirectories, onConfirm, onCancel }: Props) {
+export default function DirectoryPickerDialog({ open, initialPath, shortcutName, source, savedDirectories, onConfirm, onCancel }: Props) {
     const [selectedPath, setSelectedPath] = useState<string>("")
 
+    useEffect(() => {
+        if (open && initialPath) setSelectedPath(initialPath)
+    }, [open, initialPath])
+
     const handleBrowse = async () => {
         const path = await SelectDirectory()
         if (path) setSelectedPath(path)
@@ -46,6 +53,12 @@ export default function DirectoryPickerDialog({ open, savedDirectories, onConfir
                 <AlertDialogDescription>
                     Select a workspace directory to run the command in.
                 </AlertDialogDescription>
+                {shortcutName && (
+                    <p className="text-[12px] text-fg-faint">
+                        <span className="font-semibold text-fg">{shortcutName}</span> from{" "}
+                        <span className="mono-cell break-all text-fg-muted">{source || "your library"}</span>
+                    </p>
+                )}
 
                 {savedDirectories.length > 0 && (
                     <div className="my-2 max-h-48 space-y-1.5 overflow-y-auto">
@@ -8,6 +8,7 @@ import {
     AlertDialogAction,
 } from "@/components/ui/alert-dialog"
 import { Input } from "@/components/ui/input"
+import BuiltinsHint from "@/components/BuiltinsHint"
 import { Badge } from "@/components/ui/badge"
 import type { Shortcut } from "@/types"
 
@@ -79,6 +80,7 @@ export default function EditShortcutDialog({ shortcut, open, onSave, onClose }:
                             className="font-mono"
                             onKeyDown={(e) => e.key === "Enter" && handleSave()}
                         />
+                        <BuiltinsHint />
                     </div>
                     <div>
                         <label className="mb-1.5 block text-[13px] font-medium text-fg">
@@ -0,0 +1,102 @@
+import {
+    AlertDialog,
+    AlertDialogContent,
+    AlertDialogTitle,
+    AlertDialogDescription,
+    AlertDialogCancel,
+    AlertDialogAction,
+} from "@/components/ui/alert-dialog"
+import { Badge } from "@/components/ui/badge"
+import { Input } from "@/components/ui/input"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import type { ImportItem, ImportPlan } from "@/types"
+
+const STATUS_LABELS: Record<string, string> = {
+    new: "New",
+    identical: "Identical",
+    changed: "Command differs",
+    metadata: "Details differ",
+}
+
+interface Props {
+    plan: ImportPlan | null
+    onChange: (index: number, item: ImportItem) => void
+    onConfirm: () => void
+    onCancel: () => void
+}
+
+export default function ImportPreviewDialog({ plan, onChange, onConfirm, onCancel }: Props) {
+    const items = plan?.items ?? []
+    const counts = items.reduce<Record<string, number>>((acc, it) => ({ ...acc, [it.status]: (acc[it.status] ?? 0) + 1 }), {})
+    const renameMissing = items.some((it) => it.action === "rename" && !(it.renameTo ?? "").trim())
+    // Identical items have nothing to decide, so only list the rest.
+    const visible = items.map((it, i) => ({ it, i })).filter(({ it }) => it.status !== "identical")
+    const skipped = plan?.skipped ?? []
+
+    return (
+        <AlertDialog open={plan !== null} onOpenChange={(o) => { if (!o) onCancel() }}>
+            <AlertDialogContent className="max-w-2xl">
+                <AlertDialogTitle>Import Shortcuts</AlertDialogTitle>
+                <AlertDialogDescription>
+                    {items.length} shortcut{items.length === 1 ? "" : "s"} in{" "}
+                    <span className="mono-cell text-fg-muted">{plan?.source}</span>:{" "}
+                    {Object.entries(counts).map(([status, n]) => `${n} ${(STATUS_LABELS[status] ?? status).toLowerCase()}`).join(", ")}.
+                </AlertDialogDescription>
+
+                <div className="my-2 max-h-[50vh] space-y-2 overflow-y-auto">
+                    {visible.length === 0 && items.length > 0 && (
+                        <p className="text-[12px] text-fg-faint">Everything in this file is already in your library.</p>
+                    )}
+                    {visible.map(({ it, i }) => (
+                        <div key={it.name} className="rounded-lg border border-edge px-3 py-2">
+                            <div className="flex items-center gap-2">
+                                <span className="min-w-0 flex-1 truncate text-[13px] font-medium text-fg">{it.name}</span>
+                                <Badge variant="secondary">{STATUS_LABELS[it.status] ?? it.status}</Badge>
+                                <Select value={it.action} onValueChange={(action) => onChange(i, { ...it, action })}>
+                                    <SelectTrigger className="w-40">
+                                        <SelectValue />
+                                    </SelectTrigger>
+                                    <SelectContent>
+                                        <SelectItem value="theirs">{it.current ? "Take theirs" : "Import"}</SelectItem>
+                                        <SelectItem value="keep">{it.current ? "Keep mine" : "Skip"}</SelectItem>
+                                        <SelectItem value="rename">Import as…</SelectItem>
+                                    </SelectContent>
+                                </Select>
+                            </div>
+                            {it.current && it.status === "changed" && (
+                                <div className="mono-cell mt-1.5 space-y-0.5 text-[11px]">
+                                    <p className="truncate text-fg-faint">mine:   {it.current.command}</p>
+                                    <p className="truncate text-fg-muted">theirs: {it.incoming.command}</p>
+                                </div>
+                            )}
+                            {!it.current && (
+                                <p className="mono-cell mt-1.5 truncate text-[11px] text-fg-faint">{it.incoming.command}</p>
+                            )}
+                            {it.action === "rename" && (
+                                <Input
+                                    className="mt-2"
+                                    placeholder={`New name for ${it.name}`}
+                                    value={it.renameTo ?? ""}
+                                    onChange={(e) => onChange(i, { ...it, renameTo: e.target.value })}
+                                />
+                            )}
+                        </div>
+                    ))}
+                    {skipped.length > 0 && (
+                        <div className="rounded-lg border border-edge px-3 py-2">
+                            <p className="text-[12px] font-medium text-fg">Not imported</p>
+                            <ul className="mt-1 space-y-0.5 text-[11px] text-fg-faint">
+                                {skipped.map((s) => <li key={s}>{s}</li>)}
+                            </ul>
+                        </div>
+                    )}
+                </div>
+
+                <div className="mt-2 flex justify-end gap-2">
+                    <AlertDialogCancel>Cancel</AlertDialogCancel>
+                    <AlertDialogAction onClick={onConfirm} disabled={renameMissing}>Import</AlertDialogAction>
+                </div>
+            </AlertDialogContent>
+        </AlertDialog>
+    )
+}
@@ -1,7 +1,8 @@
+import { useEffect } from "react"
 import { Outlet } from "react-router-dom"
 import Sidebar from "./Sidebar"
-import { useLocation } from "react-router-dom"
-import { WindowSetTitle } from '../../wailsjs/runtime/runtime'
+import { useLocation, useNavigate } from "react-router-dom"
+import { WindowSetTitle, EventsOn } from '../../wailsjs/runtime/runtime'
 import { useCli, useVersion } from "@/contexts/VersionContext"
 import CliNotFoundDialog from "./CliNotFoundDialog"
 
@@ -9,6 +10,10 @@ export default function Layout() {
   const cliExists = useCli();
   const { currentVersion } = useVersion();
   const location = useLocation();
+  const navigate = useNavigate();
+
+  // Requests to run a shortcut are handled on the shortcuts page.
+  useEffect(() => EventsOn("launch:request", () => navigate("/")), [navigate]);
 
   const getPageTitle = () => {
     switch (location.pathname) {
@@ -0,0 +1,127 @@
+import { useEffect, useState } from "react"
+import {
+    AlertDialog,
+    AlertDialogContent,
+    AlertDialogTitle,
+    AlertDialogDescription,
+    AlertDialogCancel,
+} from "@/components/ui/alert-dialog"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { FolderOpen, Play, BookmarkPlus } from "lucide-react"
+import type { ProjectTask, SavedDir } from "@/types"
+import { DiscoverProjectTasks, PromoteProjectTask, SelectDirectory } from "../../wailsjs/go/main/App"
+
+interface Props {
+    open: boolean
+    initialDir: string
+    savedDirectories: SavedDir[]
+    onRun: (task: ProjectTask, dirPath: string) => void
+    onClose: () => void
+}
+
+export default function ProjectTasksDialog({ open, initialDir, savedDirectories, onRun, onClose }: Props) {
+    const [dir, setDir] = useState("")
+    const [tasks, setTasks] = useState<ProjectTask[]>([])
+    const [error, setError] = useState("")
+    const [saved, setSaved] = useState<Record<string, boolean>>({})
+
+    useEffect(() => {
+        if (open) setDir((d) => d || initialDir)
+    }, [open, initialDir])
+
+    useEffect(() => {
+        if (!open || !dir) return
+        DiscoverProjectTasks(dir)
+            .then((found) => {
+                setTasks(found ?? [])
+                setError("")
+            })
+            .catch((err) => {
+                setTasks([])
+                setError(String(err))
+            })
+        setSaved({})
+    }, [open, dir])
+
+    const handleBrowse = async () => {
+        const path = await SelectDirectory()
+        if (path) setDir(path)
+    }
+
+    const handleSave = async (task: ProjectTask) => {
+        try {
+            await PromoteProjectTask(dir, task.name, task.name)
+            setSaved((prev) => ({ ...prev, [task.name]: true }))
+        } catch (err) {
+            alert(`Could not save the task: ${err}`)
+        }
+    }
+
+    return (
+        <AlertDialog open={open} onOpenChange={(o) => { if (!o) onClose() }}>
+            <AlertDialogContent className="max-w-2xl">
+                <AlertDialogTitle>Project Tasks</AlertDialogTitle>
+                <AlertDialogDescription>
+                    Tasks from the Makefile, package.json, justfile, Taskfile, Cargo.toml or go.mod in a directory.
+                    Run one directly or save it as a shortcut.
+                </AlertDialogDescription>
+
+                <div className="flex flex-wrap items-center gap-2">
+                    {savedDirectories.map((d) => (
+                        <Button
+                            key={d.name}
+                            variant={d.path === dir ? "secondary" : "outline"}
+                            size="sm"
+                            onClick={() => setDir(d.path)}
+                            title={d.path}
+                        >
+                            {d.name}
+                        </Button>
+                    ))}
+                    <Button variant="ghost" size="sm" onClick={handleBrowse}>
+                        <FolderOpen className="h-4 w-4" />
+                        Browse…
+                    </Button>
+                </div>
+                {dir && <p className="mono-cell truncate text-[11px] text-fg-faint">{dir}</p>}
+
+                <div className="my-2 max-h-[50vh] space-y-1.5 overflow-y-auto">
+                    {error && <p className="text-[12px] text-danger">{error}</p>}
+                    {!error && dir && tasks.length === 0 && (
+                        <p className="text-[12px] text-fg-faint">No tasks found in this directory.</p>
+                    )}
+                    {tasks.map((task) => (
+                        <div key={task.name} className="flex items-center gap-2 rounded-lg border border-edge px-3 py-2">
+                            <div className="min-w-0 flex-1">
+                                <p className="flex items-center gap-2 text-[13px] font-medium text-fg">
+                                    <span className="truncate">{task.name}</span>
+                                    <Badge variant="secondary">{task.source}</Badge>
+                                </p>
+                                <p className="mono-cell truncate text-[11px] text-fg-muted">{task.command}</p>
+                                {task.description && <p className="truncate text-[11px] text-fg-faint">{task.description}</p>}
+                            </div>
+                            <Button
+                                variant="ghost"
+                                size="icon-sm"
+                                onClick={() => handleSave(task)}
+                                disabled={saved[task.name]}
+                                title={saved[task.name] ? "Saved" : "Save as shortcut"}
+                                aria-label="Save as shortcut"
+                            >
+                                <BookmarkPlus className="h-4 w-4" />
+                            </Button>
+                            <Button variant="ghost" size="icon-sm" onClick={() => onRun(task, dir)} title="Run" aria-label="Run">
+                                <Play className="h-4 w-4" />
+                            </Button>
+                        </div>
+                    ))}
+                </div>
+
+                <div className="flex justify-end">
+                    <AlertDialogCancel onClick={onClose}>Close</AlertDialogCancel>
+                </div>
+            </AlertDialogContent>
+        </AlertDialog>
+    )
+}
@@ -6,19 +6,26 @@ import {
     AlertDialogCancel,
     AlertDialogAction,
 } from "@/components/ui/alert-dialog"
+import { Button } from "@/components/ui/button"
 import { Input } from "@/components/ui/input"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import type { Placeholder } from "@/types"
 
 interface Props {
     open: boolean
-    variables: string[]
+    variables: Placeholder[]
     values: Record<string, string>
+    history: Record<string, string[]>
     onChange: (variable: string, value: string) => void
+    onUseLast: () => void
     onConfirm: () => void
     onCancel: () => void
 }
 
-export default function VarSubstitutionDialog({ open, variables, values, onChange, onConfirm, onCancel }: Props) {
-    const allFilled = variables.every((v) => (values[v] ?? "").trim() !== "")
+export default function VarSubstitutionDialog({ open, variables, values, history, onChange, onUseLast, onConfirm, onCancel }: Props) {
+    const hasHistory = variables.some((v) => (history[v.name] ?? []).length > 0)
+
+    const allFilled = variables.every((v) => !v.required || (values[v.name] ?? "").trim() !== "")
 
     return (
         <AlertDialog open={open} onOpenChange={(o) => { if (!o) onCancel() }}>
@@ -30,22 +37,52 @@ export default function VarSubstitutionDialog({ open, variables, values, onChang
 
                 <div className="my-2 space-y-4">
                     {variables.map((variable, i) => (
-                        <div key={variable}>
+                        <div key={variable.name}>
                             <label className="mono-cell mb-1.5 block text-[13px] font-medium text-accent-soft">
-                                {"{"}{variable}{"}"}
+                                {"{"}{variable.name}{"}"}
+                                {!variable.required && <span className="ml-1.5 text-[11px] font-normal text-fg-faint">optional</span>}
                             </label>
-                            <Input
-                                placeholder={`Value for ${variable}`}
-                                value={values[variable] ?? ""}
-                                onChange={(e) => onChange(variable, e.target.value)}
-                                className="font-mono"
-                                autoFocus={i === 0}
-                            />
+                            {variable.type === "choice" ? (
+                                <Select
+                                    value={values[variable.name] || variable.default || undefined}
+                                    onValueChange={(val) => onChange(variable.name, val)}
+                                >
+                                    <SelectTrigger className="w-full font-mono" autoFocus={i === 0}>
+                                        <SelectValue placeholder={`Choose ${variable.name}`} />
+                                    </SelectTrigger>
+                                    <SelectContent>
+                                        {(variable.choices ?? []).map((choice) => (
+                                            <SelectItem key={choice} value={choice}>{choice}</SelectItem>
+                                        ))}
+                                    </SelectContent>
+                                </Select>
+                            ) : (
+                                <>
+                                    <Input
+                                        placeholder={variable.default ? `Default: ${variable.default}` : `Value for ${variable.name}`}
+                                        value={values[variable.name] ?? ""}
+                                        onChange={(e) => onChange(variable.name, e.target.value)}
+                                        className="font-mono"
+                                        autoFocus={i === 0}
+                                        list={`var-history-${variable.name}`}
+                                    />
+                                    <datalist id={`var-history-${variable.name}`}>
+                                        {(history[variable.name] ?? []).map((recent) => (
+                                            <option key={recent} value={recent} />
+                                        ))}
+                                    </datalist>
+                                </>
+                            )}
                         </div>
                     ))}
                 </div>
 
                 <div className="mt-2 flex justify-end gap-2">
+                    {hasHistory && (
+                        <Button variant="ghost" className="mr-auto" onClick={onUseLast}>
+                            Use last values
+                        </Button>
+                    )}
                     <AlertDialogCancel onClick={onCancel}>Cancel</AlertDialogCancel>
                     <AlertDialogAction onClick={onConfirm} disabled={!allFilled}>
                         Continue →
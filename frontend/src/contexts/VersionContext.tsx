@@ -1,6 +1,6 @@
 import { createContext, useCallback, useContext, useEffect, useState } from "react";
 import { GetVersion, CliExists, GetConfig } from "../../wailsjs/go/main/App";
-import { LogPrint } from "../../wailsjs/runtime/runtime";
+import { EventsOn, LogPrint } from "../../wailsjs/runtime/runtime";
 import type { AppConfig } from "@/types";
 
 interface UpdateInfo {
@@ -81,6 +81,9 @@ export const AppContextProvider = ({ children }: { children: React.ReactNode })
     refreshConfig();
   }, [refreshConfig]);
 
+  // Reload when config.json is changed outside the app.
+  useEffect(() => EventsOn("config:changed", refreshConfig), [refreshConfig]);
+
   return (
     <AppConfigContext.Provider value={{ config, refreshConfig }}>
       <CliContext.Provider value={cliExists}>
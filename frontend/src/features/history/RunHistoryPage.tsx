@@ -1,6 +1,7 @@
 import { useState, useEffect } from "react"
 import { Clock, Trash2, FolderOpen, Terminal } from "lucide-react"
 import { Button } from "@/components/ui/button"
+import { Badge } from "@/components/ui/badge"
 import {
     AlertDialog,
     AlertDialogAction,
@@ -12,6 +13,7 @@ import {
 } from "@/components/ui/alert-dialog"
 import type { RunHistoryEntry } from "@/types"
 import { GetRunHistory, ClearRunHistory } from "../../../wailsjs/go/main/App"
+import { EventsOn } from "../../../wailsjs/runtime/runtime"
 
 function formatTimestamp(ts: string): { date: string; time: string } {
     const d = new Date(ts)
@@ -21,6 +23,13 @@ function formatTimestamp(ts: string): { date: string; time: string } {
     }
 }
 
+function formatDuration(ms: number): string {
+    if (ms < 1000) return `${ms}ms`
+    const s = ms / 1000
+    if (s < 60) return `${s.toFixed(1)}s`
+    return `${Math.floor(s / 60)}m ${Math.round(s % 60)}s`
+}
+
 function SkeletonRow() {
     return (
         <div className="flex items-center gap-4 border-b border-edge px-4 py-4">
@@ -40,6 +49,11 @@ export default function RunHistoryPage() {
 
     useEffect(() => { loadHistory() }, [])
 
+    // Silent refresh when history.json changes (a run finished, or the CLI ran something).
+    useEffect(() => EventsOn("history:changed", () => {
+        GetRunHistory().then(setHistory).catch((err) => console.error("Error loading history:", err))
+    }), [])
+
     const loadHistory = async () => {
         setLoading(true)
         try {
@@ -135,7 +149,18 @@ export default function RunHistoryPage() {
                                                 <div className="mt-1.5 flex items-center gap-1.5">
                                                     <FolderOpen className="h-3 w-3 shrink-0 text-fg-faint" />
                                                     <span className="truncate text-[11px] text-fg-faint">{entry.directory}</span>
+                                                    {entry.exitCode !== undefined && (
+                                                        <Badge variant={entry.exitCode === 0 ? "success" : "destructive"} className="mono-cell ml-auto text-[10px]">
+                                                            exit {entry.exitCode}
+                                                            {entry.durationMs !== undefined && ` · ${formatDuration(entry.durationMs)}`}
+                                                        </Badge>
+                                                    )}
                                                 </div>
+                                                {entry.exitCode !== undefined && entry.exitCode !== 0 && entry.outputTail && entry.outputTail.length > 0 && (
+                                                    <pre className="mono-cell mt-1.5 max-h-32 overflow-auto whitespace-pre-wrap rounded-md border border-edge bg-surface-2 px-2 py-1.5 text-[11px] text-fg-faint">
+                                                        {entry.outputTail.join("\n")}
+                                                    </pre>
+                                                )}
                                             </td>
                                         </tr>
                                     )
@@ -5,10 +5,8 @@ import {
     filterShortcuts,
     truncateCommand,
     collectAllTags,
-    extractVariables,
-    substituteVariables,
 } from "@/lib/shortcutHelpers"
-import { Edit2, Trash2, Search, Terminal, Star, Copy, Tag, Plus, X } from "lucide-react"
+import { Edit2, Trash2, Search, Terminal, Star, Copy, Tag, Plus, X, Undo2, Redo2, ListChecks, FolderGit2 } from "lucide-react"
 import { Button } from "@/components/ui/button"
 import { cn } from "@/lib/utils"
 import {
@@ -24,18 +22,31 @@ import VarSubstitutionDialog from "@/components/VarSubstitutionDialog"
 import DirectoryPickerDialog from "@/components/DirectoryPickerDialog"
 import EditShortcutDialog from "@/components/EditShortcutDialog"
 import AddShortcutDialog from "@/components/AddShortcutDialog"
+import ProjectTasksDialog from "@/components/ProjectTasksDialog"
 import { useAppConfig } from "@/contexts/VersionContext"
-import type { Shortcut, ShortcutData } from "@/types"
+import type { LaunchRequest, Placeholder, ProjectShadow, ProjectTask, RecentChanges, Shortcut, ShortcutData } from "@/types"
 
 import {
-    GetShortcuts,
+    GetShortcutsForDir,
+    GetUntrustedProjectFiles,
+    TrustProjectFile,
     AddShortcut,
     UpdateShortcut,
     RemoveShortcut,
     TogglePinShortcut,
     DuplicateShortcut,
     ApplyShortcut,
+    ApplyProjectTask,
+    ParseShortcutVariables,
+    ParseCommandVariables,
+    RenderCommand,
+    GetVariableHistory,
+    Undo,
+    Redo,
+    GetRecentChanges,
+    TakeLaunchRequests,
 } from "../../../wailsjs/go/main/App"
+import { EventsOn } from "../../../wailsjs/runtime/runtime"
 
 function TagPill({ label, active, onClick }: { label: string; active: boolean; onClick: () => void }) {
     return (
@@ -57,14 +68,24 @@ function TagPill({ label, active, onClick }: { label: string; active: boolean; o
 interface VarDialogState {
     open: boolean
     shortcut: Shortcut | null
-    variables: string[]
+    variables: Placeholder[]
+    values: Record<string, string>
+    history: Record<string, string[]>
+    dirPath?: string    // preselected directory, e.g. from a launch request
+}
+
+interface TaskRunState {
+    task: ProjectTask
+    dirPath: string
+    variables: Placeholder[]
     values: Record<string, string>
 }
 
 interface DirDialogState {
     open: boolean
     shortcut: Shortcut | null
-    interpolatedCommand: string
+    values: Record<string, string>
+    dirPath?: string
 }
 
 export default function ShortcutsPage() {
@@ -78,31 +99,68 @@ export default function ShortcutsPage() {
     const [editDialog, setEditDialog] = useState<{ open: boolean; shortcut: Shortcut | null }>({ open: false, shortcut: null })
 
     const [varDialog, setVarDialog] = useState<VarDialogState>({
-        open: false, shortcut: null, variables: [], values: {},
+        open: false, shortcut: null, variables: [], values: {}, history: {},
     })
     const [dirDialog, setDirDialog] = useState<DirDialogState>({
-        open: false, shortcut: null, interpolatedCommand: "",
+        open: false, shortcut: null, values: {},
     })
 
+    const [tasksOpen, setTasksOpen] = useState(false)
+    // A yagui:// link waiting for the user to agree to run its shortcut.
+    const [linkRun, setLinkRun] = useState<{ req: LaunchRequest; shortcut: Shortcut } | null>(null)
+    // Launch requests not yet handled, and whether one is being started.
+    const [launchQueue, setLaunchQueue] = useState<LaunchRequest[]>([])
+    const [launchBusy, setLaunchBusy] = useState(false)
+    // Project files kept from replacing library shortcuts, and the sums of
+    // the contents the user chose not to trust this session.
+    const [untrusted, setUntrusted] = useState<ProjectShadow[]>([])
+    const [keptLibrary, setKeptLibrary] = useState<string[]>([])
+    const [taskRun, setTaskRun] = useState<TaskRunState | null>(null)
+
     const commandMaxLength = useCommandMaxLength()
 
     const searchRef = useRef<HTMLInputElement>(null)
     const rowRefs = useRef<(HTMLTableRowElement | null)[]>([])
     const [selectedIndex, setSelectedIndex] = useState(0)
 
+    const [recent, setRecent] = useState<RecentChanges>({ undo: [], redo: [] })
+
+    const loadRecent = () => {
+        GetRecentChanges().then(setRecent).catch((err) => console.error("Error loading recent changes:", err))
+    }
+
+    // Project shortcuts are those of the directory used last.
+    const projectDir = config.defaultDir ?? ""
+
     const loadShortcuts = async () => {
         try {
-            setShortcuts(await GetShortcuts())
+            setShortcuts(await GetShortcutsForDir(projectDir))
         } catch (err) {
             console.error("Error loading shortcuts:", err)
         }
+        loadRecent()
+    }
+
+    const loadUntrusted = () => {
+        GetUntrustedProjectFiles(projectDir)
+            .then((s) => setUntrusted(s ?? []))
+            .catch((err) => console.error("Error checking project files:", err))
     }
 
     // Load shortcuts on mount; loadShortcuts is async, so the state update
     // happens in the promise callback, not synchronously in the effect.
     useEffect(() => {
-        GetShortcuts().then(setShortcuts).catch((err) => console.error("Error loading shortcuts:", err))
-    }, [])
+        GetShortcutsForDir(projectDir).then(setShortcuts).catch((err) => console.error("Error loading shortcuts:", err))
+        loadUntrusted()
+        loadRecent()
+    }, [projectDir])
+
+    // Live reload when the shortcuts are changed elsewhere, e.g. `ya add` in a terminal.
+    useEffect(() => EventsOn("shortcuts:changed", () => {
+        GetShortcutsForDir(projectDir).then(setShortcuts).catch((err) => console.error("Error loading shortcuts:", err))
+        loadUntrusted()
+        loadRecent()
+    }), [projectDir])
 
     // Keep the selected row in view as the user navigates with the arrows.
     useEffect(() => {
@@ -120,8 +178,8 @@ export default function ShortcutsPage() {
     }
 
     const handleAddShortcut = async (name: string, command: string, description: string, tags: string) => {
-        const updated = await AddShortcut(name, command, description, tags)
-        setShortcuts(updated)
+        await AddShortcut(name, command, description, tags)
+        await loadShortcuts()
     }
 
     const handleSaveEdit = async (oldName: string, newName: string, command: string, description: string, tags: string) => {
@@ -149,39 +207,175 @@ export default function ShortcutsPage() {
 
     const handleDuplicate = async (name: string) => {
         try {           
-            const updated = await DuplicateShortcut(name)
-            setShortcuts(updated)
+            await DuplicateShortcut(name)
+            await loadShortcuts()
         } catch (err) {
             console.error("Error duplicating shortcut:", err)
         }
     }
 
-    const startRun = (shortcut: Shortcut) => {
-        const variables = extractVariables(shortcut.command)
+    const handleUndo = async (redo = false) => {
+        try {
+            await (redo ? Redo() : Undo())
+        } catch (err) {
+            alert(String(err))
+        }
+        await loadShortcuts()
+    }
+
+    // Ctrl/Cmd+Z undoes, Ctrl/Cmd+Shift+Z or Ctrl+Y redoes — unless a dialog
+    // is open or a text field has something its own undo should handle.
+    useEffect(() => {
+        const onKeyDown = (e: globalThis.KeyboardEvent) => {
+            if (!(e.ctrlKey || e.metaKey)) return
+            const key = e.key.toLowerCase()
+            if (key !== "z" && key !== "y") return
+            const el = e.target
+            if ((el instanceof HTMLInputElement || el instanceof HTMLTextAreaElement) && el.value) return
+            if (document.querySelector('[role="dialog"], [role="alertdialog"]')) return
+            e.preventDefault()
+            handleUndo(key === "y" || e.shiftKey)
+        }
+        window.addEventListener("keydown", onKeyDown)
+        return () => window.removeEventListener("keydown", onKeyDown)
+    })
+
+    const startRun = async (shortcut: Shortcut, preset: { values?: Record<string, string>; dirPath?: string } = {}) => {
+        let variables: Placeholder[]
+        try {
+            // Project shortcuts are not in the library, so go by their command.
+            variables = await (shortcut.source ? ParseCommandVariables(shortcut.command) : ParseShortcutVariables(shortcut.name))
+        } catch (err) {
+            alert(`Invalid placeholder in command: ${err}`)
+            return
+        }
         if (variables.length > 0) {
-            setVarDialog({ open: true, shortcut, variables, values: {} })
+            const history = shortcut.source
+                ? {}
+                : await GetVariableHistory(shortcut.name).catch(() => ({} as Record<string, string[]>))
+            setVarDialog({ open: true, shortcut, variables, values: preset.values ?? {}, history, dirPath: preset.dirPath })
         } else {
-            setDirDialog({ open: true, shortcut, interpolatedCommand: shortcut.command })
+            setDirDialog({ open: true, shortcut, values: {}, dirPath: preset.dirPath })
         }
     }
 
-    const handleVarConfirm = () => {
-        const { shortcut, values } = varDialog
+    // Another launch of the app (`yagui --run NAME ...`) or a yagui:// link
+    // asked to run a shortcut: start the normal run flow, which the user
+    // still confirms. Links could come from anywhere, so they are shown to
+    // the user before even that.
+    const handleLaunchRequest = async (req: LaunchRequest) => {
+        if (req.error) {
+            alert(`Cannot open ${req.url}: ${req.error}`)
+            return
+        }
+        try {
+            const all = await GetShortcutsForDir(req.dir || projectDir)
+            if (!all[req.name]) {
+                alert(`Shortcut "${req.name}" not found.`)
+                return
+            }
+            const [shortcut] = formatShortcuts({ [req.name]: all[req.name] })
+            if (req.url) {
+                setLinkRun({ req, shortcut })
+                return
+            }
+            await startRun(shortcut, { values: req.values, dirPath: req.dir })
+        } catch (err) {
+            alert(String(err))
+        }
+    }
+
+    const takeLaunchRequests = async () => {
+        const requests = (await TakeLaunchRequests().catch(() => null)) ?? []
+        if (requests.length > 0) setLaunchQueue((q) => [...q, ...requests])
+    }
+
+    useEffect(() => {
+        takeLaunchRequests()
+        return EventsOn("launch:request", takeLaunchRequests)
+    }, [])
+
+    const runFlowOpen = linkRun !== null || varDialog.open || dirDialog.open
+
+    // A .ya.json may only replace library shortcuts once the user trusts it,
+    // and again each time it changes.
+    const shadow = untrusted.find((s) => !keptLibrary.includes(s.sum))
+    const handleTrust = async (s: ProjectShadow) => {
+        try {
+            await TrustProjectFile(s.file, s.sum)
+        } catch (err) {
+            alert(String(err))
+        }
+    }
+
+    // Requests are handled in the order they came in, each once the run
+    // flow of the one before it has been finished or cancelled.
+    useEffect(() => {
+        if (launchBusy || runFlowOpen || launchQueue.length === 0) return
+        setLaunchBusy(true)
+        handleLaunchRequest(launchQueue[0]).finally(() => {
+            setLaunchQueue((q) => q.slice(1))
+            setLaunchBusy(false)
+        })
+    }, [launchQueue, launchBusy, runFlowOpen])
+
+    const handleVarConfirm = async () => {
+        const { shortcut, values, dirPath } = varDialog
         if (!shortcut) return
-        const interpolated = substituteVariables(shortcut.command, values)
-        setVarDialog({ open: false, shortcut: null, variables: [], values: {} })
-        setDirDialog({ open: true, shortcut, interpolatedCommand: interpolated })
+        try {
+            // Validate the values before asking for a directory; project
+            // shortcuts are validated when they are run.
+            if (!shortcut.source) await RenderCommand(shortcut.name, values)
+        } catch (err) {
+            alert(String(err))
+            return
+        }
+        setVarDialog({ open: false, shortcut: null, variables: [], values: {}, history: {} })
+        setDirDialog({ open: true, shortcut, values, dirPath })
     }
 
     const handleDirConfirm = async (dirPath: string) => {
-        const { shortcut, interpolatedCommand } = dirDialog
+        const { shortcut, values } = dirDialog
         if (!shortcut) return
-        setDirDialog({ open: false, shortcut: null, interpolatedCommand: "" })
-        const ok = await ApplyShortcut(shortcut.name, interpolatedCommand, dirPath)
-        if (!ok) alert("Failed to launch the shortcut command.")
+        setDirDialog({ open: false, shortcut: null, values: {} })
+        try {
+            await ApplyShortcut(shortcut.name, shortcut.source ?? "", dirPath, values)
+        } catch (err) {
+            alert(`Failed to launch the shortcut command: ${err}`)
+        }
         await loadShortcuts()
     }
 
+    const runTask = async (task: ProjectTask, dirPath: string, values: Record<string, string>) => {
+        try {
+            await ApplyProjectTask(dirPath, task.name, values)
+        } catch (err) {
+            alert(`Failed to launch the task: ${err}`)
+        }
+    }
+
+    const handleTaskRun = async (task: ProjectTask, dirPath: string) => {
+        let variables: Placeholder[]
+        try {
+            variables = await ParseCommandVariables(task.command)
+        } catch (err) {
+            alert(`Invalid placeholder in command: ${err}`)
+            return
+        }
+        setTasksOpen(false)
+        if (variables.length > 0) {
+            setTaskRun({ task, dirPath, variables, values: {} })
+        } else {
+            await runTask(task, dirPath, {})
+        }
+    }
+
+    const handleTaskVarConfirm = async () => {
+        if (!taskRun) return
+        setTaskRun(null)
+        await runTask(taskRun.task, taskRun.dirPath, taskRun.values)
+    }
+
     const allShortcuts = formatShortcuts(shortcuts)
     const allTags = collectAllTags(allShortcuts)
     const filtered = filterShortcuts(allShortcuts, searchQuery, activeTag ?? undefined)
@@ -231,15 +425,105 @@ export default function ShortcutsPage() {
                 open={varDialog.open}
                 variables={varDialog.variables}
                 values={varDialog.values}
+                history={varDialog.history}
                 onChange={(v, val) => setVarDialog((prev) => ({ ...prev, values: { ...prev.values, [v]: val } }))}
+                onUseLast={() => setVarDialog((prev) => ({
+                    ...prev,
+                    values: Object.fromEntries(
+                        Object.entries(prev.history).map(([name, recent]) => [name, recent[0] ?? ""]),
+                    ),
+                }))}
                 onConfirm={handleVarConfirm}
-                onCancel={() => setVarDialog({ open: false, shortcut: null, variables: [], values: {} })}
+                onCancel={() => setVarDialog({ open: false, shortcut: null, variables: [], values: {}, history: {} })}
+            />
+            <VarSubstitutionDialog
+                open={taskRun !== null}
+                variables={taskRun?.variables ?? []}
+                values={taskRun?.values ?? {}}
+                history={{}}
+                onChange={(v, val) => setTaskRun((prev) => prev && { ...prev, values: { ...prev.values, [v]: val } })}
+                onUseLast={() => {}}
+                onConfirm={handleTaskVarConfirm}
+                onCancel={() => setTaskRun(null)}
+            />
+            <AlertDialog open={linkRun !== null} onOpenChange={(o) => { if (!o) setLinkRun(null) }}>
+                <AlertDialogContent className="max-w-xl">
+                    <AlertDialogTitle>Run Shortcut from Link?</AlertDialogTitle>
+                    <AlertDialogDescription>
+                        A <span className="font-semibold text-fg">yagui://</span> link asked to run{" "}
+                        <span className="font-semibold text-fg">{linkRun?.shortcut.name}</span>. Only continue if you trust where it came from.
+                    </AlertDialogDescription>
+                    {linkRun && (
+                        <div className="space-y-2 text-[12px]">
+                            <p className="mono-cell break-all rounded-md bg-surface-3 px-3 py-2 text-fg">{linkRun.shortcut.command}</p>
+                            <p className="text-fg-faint">From <span className="mono-cell break-all text-fg-muted">{linkRun.shortcut.source || "your library"}</span></p>
+                            {linkRun.req.dir && (
+                                <p className="text-fg-faint">In <span className="mono-cell text-fg-muted">{linkRun.req.dir}</span></p>
+                            )}
+                            {Object.entries(linkRun.req.values ?? {}).map(([k, v]) => (
+                                <p key={k} className="text-fg-faint">
+                                    <span className="mono-cell text-fg-muted">{k}</span> = <span className="mono-cell break-all text-fg">{v}</span>
+                                </p>
+                            ))}
+                            <p className="mono-cell break-all text-[11px] text-fg-faint">{linkRun.req.url}</p>
+                        </div>
+                    )}
+                    <div className="mt-2 flex justify-end gap-2">
+                        <AlertDialogCancel>Cancel</AlertDialogCancel>
+                        <AlertDialogAction
+                            onClick={() => {
+                                if (!linkRun) return
+                                const { req, shortcut } = linkRun
+                                setLinkRun(null)
+                                setLaunchBusy(true)
+                                startRun(shortcut, { values: req.values, dirPath: req.dir }).finally(() => setLaunchBusy(false))
+                            }}
+                        >
+                            Continue
+                        </AlertDialogAction>
+                    </div>
+                </AlertDialogContent>
+            </AlertDialog>
+            <AlertDialog
+                open={shadow !== undefined && !runFlowOpen}
+                onOpenChange={(o) => { if (!o && shadow) setKeptLibrary((k) => [...k, shadow.sum]) }}
+            >
+                <AlertDialogContent className="max-w-xl">
+                    <AlertDialogTitle>Trust Project Shortcuts?</AlertDialogTitle>
+                    <AlertDialogDescription>
+                        <span className="mono-cell break-all text-fg">{shadow?.file}</span> redefines shortcuts from your library.
+                        Until you trust it as it is now, your own versions are used.
+                    </AlertDialogDescription>
+                    {shadow && (
+                        <div className="flex flex-wrap gap-1.5 text-[12px]">
+                            {shadow.names.map((name) => (
+                                <span key={name} className="mono-cell rounded-md bg-surface-3 px-2 py-0.5 text-fg">{name}</span>
+                            ))}
+                        </div>
+                    )}
+                    <div className="mt-2 flex justify-end gap-2">
+                        <AlertDialogCancel>Keep Mine</AlertDialogCancel>
+                        <AlertDialogAction onClick={() => { if (shadow) handleTrust(shadow) }}>
+                            Trust File
+                        </AlertDialogAction>
+                    </div>
+                </AlertDialogContent>
+            </AlertDialog>
+            <ProjectTasksDialog
+                open={tasksOpen}
+                initialDir={config.defaultDir ?? ""}
+                savedDirectories={config.savedDirectories ?? []}
+                onRun={handleTaskRun}
+                onClose={() => setTasksOpen(false)}
             />
             <DirectoryPickerDialog
                 open={dirDialog.open}
+                initialPath={dirDialog.dirPath || (dirDialog.shortcut?.source ? projectDir : "")}
+                shortcutName={dirDialog.shortcut?.name}
+                source={dirDialog.shortcut?.source}
                 savedDirectories={config.savedDirectories ?? []}
                 onConfirm={handleDirConfirm}
-                onCancel={() => setDirDialog({ open: false, shortcut: null, interpolatedCommand: "" })}
+                onCancel={() => setDirDialog({ open: false, shortcut: null, values: {} })}
             />
 
             <section className="flex min-h-0 flex-1 flex-col overflow-hidden rounded-xl border border-edge bg-surface shadow-[var(--shadow-panel)]">
@@ -286,7 +570,33 @@ export default function ShortcutsPage() {
                             ))}
                         </div>
                     )}
-                    <Button onClick={() => setAddDialogOpen(true)} size="sm" className="ml-auto shrink-0">
+                    <div className="ml-auto flex shrink-0 items-center">
+                        <Button
+                            variant="ghost"
+                            size="icon-sm"
+                            onClick={() => handleUndo()}
+                            disabled={recent.undo.length === 0}
+                            title={recent.undo[0] ? `Undo ${recent.undo[0].op}` : "Nothing to undo"}
+                            aria-label="Undo"
+                        >
+                            <Undo2 className="h-4 w-4" />
+                        </Button>
+                        <Button
+                            variant="ghost"
+                            size="icon-sm"
+                            onClick={() => handleUndo(true)}
+                            disabled={recent.redo.length === 0}
+                            title={recent.redo[0] ? `Redo ${recent.redo[0].op}` : "Nothing to redo"}
+                            aria-label="Redo"
+                        >
+                            <Redo2 className="h-4 w-4" />
+                        </Button>
+                    </div>
+                    <Button variant="outline" onClick={() => setTasksOpen(true)} size="sm" className="shrink-0" title="Project tasks">
+                        <ListChecks className="h-4 w-4" />
+                        <span className="hidden sm:inline">Tasks</span>
+                    </Button>
+                    <Button onClick={() => setAddDialogOpen(true)} size="sm" className="shrink-0">
                         <Plus className="h-4 w-4" />
                         <span className="hidden sm:inline">New Shortcut</span>
                     </Button>
@@ -319,6 +629,15 @@ export default function ShortcutsPage() {
                                                 {shortcut.pinned && <Star className="h-3 w-3 shrink-0 fill-pin text-pin" />}
                                                 <span className="truncate">{shortcut.name}</span>
                                             </span>
+                                            {shortcut.source && (
+                                                <span
+                                                    className="mt-1 flex items-center gap-1 text-[10px] text-accent-soft"
+                                                    title={`From ${shortcut.source} — edit it there`}
+                                                >
+                                                    <FolderGit2 className="h-3 w-3 shrink-0" />
+                                                    project
+                                                </span>
+                                            )}
                                         </td>
                                         <td className="w-full flex-none px-3 py-1 align-top sm:table-cell sm:min-w-0 sm:px-4 sm:py-3">
                                             <code
@@ -354,48 +673,50 @@ export default function ShortcutsPage() {
                                                 >
                                                     <Terminal className="h-4 w-4" />
                                                 </Button>
-                                                <Button
-                                                    variant="ghost"
-                                                    size="icon-sm"
-                                                    onClick={(e) => { e.stopPropagation(); setEditDialog({ open: true, shortcut }) }}
-                                                    title="Edit"
-                                                >
-                                                    <Edit2 className="h-4 w-4" />
-                                                </Button>
-                                                <Button
-                                                    variant="ghost"
-                                                    size="icon-sm"
-                                                    onClick={(e) => { e.stopPropagation(); handleDuplicate(shortcut.name) }}
-                                                    title="Duplicate"
-                                                >
-                                                    <Copy className="h-4 w-4" />
-                                                </Button>
-                                                <Button
-                                                    variant="ghost"
-                                                    size="icon-sm"
-                                                    onClick={(e) => { e.stopPropagation(); handleTogglePin(shortcut.name) }}
-                                                    title={shortcut.pinned ? "Unpin" : "Pin to top"}
-                                                    className={shortcut.pinned ? "text-pin hover:text-pin" : "text-fg-faint hover:text-pin"}
-                                                >
-                                                    <Star className={`h-4 w-4 ${shortcut.pinned ? "fill-pin" : ""}`} />
-                                                </Button>
-                                                <AlertDialog>
-                                                    <AlertDialogTrigger asChild>
-                                                        <Button variant="danger-ghost" size="icon-sm" className="text-fg-faint" title="Delete">
-                                                            <Trash2 className="h-4 w-4" />
-                                                        </Button>
-                                                    </AlertDialogTrigger>
-                                                    <AlertDialogContent>
-                                                        <AlertDialogTitle>Delete Shortcut</AlertDialogTitle>
-                                                        <AlertDialogDescription>
-                                                            Delete <span className="font-semibold text-fg">{shortcut.name}</span>? This cannot be undone.
-                                                        </AlertDialogDescription>
-                                                        <div className="mt-2 flex justify-end gap-2">
-                                                            <AlertDialogCancel>Cancel</AlertDialogCancel>
-                                                            <AlertDialogAction className="bg-danger-strong hover:bg-danger" onClick={() => handleRemoveShortcut(shortcut.name)}>Delete</AlertDialogAction>
-                                                        </div>
-                                                    </AlertDialogContent>
-                                                </AlertDialog>
+                                                {!shortcut.source && (<>
+                                                    <Button
+                                                        variant="ghost"
+                                                        size="icon-sm"
+                                                        onClick={(e) => { e.stopPropagation(); setEditDialog({ open: true, shortcut }) }}
+                                                        title="Edit"
+                                                    >
+                                                        <Edit2 className="h-4 w-4" />
+                                                    </Button>
+                                                    <Button
+                                                        variant="ghost"
+                                                        size="icon-sm"
+                                                        onClick={(e) => { e.stopPropagation(); handleDuplicate(shortcut.name) }}
+                                                        title="Duplicate"
+                                                    >
+                                                        <Copy className="h-4 w-4" />
+                                                    </Button>
+                                                    <Button
+                                                        variant="ghost"
+                                                        size="icon-sm"
+                                                        onClick={(e) => { e.stopPropagation(); handleTogglePin(shortcut.name) }}
+                                                        title={shortcut.pinned ? "Unpin" : "Pin to top"}
+                                                        className={shortcut.pinned ? "text-pin hover:text-pin" : "text-fg-faint hover:text-pin"}
+                                                    >
+                                                        <Star className={`h-4 w-4 ${shortcut.pinned ? "fill-pin" : ""}`} />
+                                                    </Button>
+                                                    <AlertDialog>
+                                                        <AlertDialogTrigger asChild>
+                                                            <Button variant="danger-ghost" size="icon-sm" className="text-fg-faint" title="Delete">
+                                                                <Trash2 className="h-4 w-4" />
+                                                            </Button>
+                                                        </AlertDialogTrigger>
+                                                        <AlertDialogContent>
+                                                            <AlertDialogTitle>Delete Shortcut</AlertDialogTitle>
+                                                            <AlertDialogDescription>
+                                                                Delete <span className="font-semibold text-fg">{shortcut.name}</span>? It will be moved to the trash.
+                                                            </AlertDialogDescription>
+                                                            <div className="mt-2 flex justify-end gap-2">
+                                                                <AlertDialogCancel>Cancel</AlertDialogCancel>
+                                                                <AlertDialogAction className="bg-danger-strong hover:bg-danger" onClick={() => handleRemoveShortcut(shortcut.name)}>Delete</AlertDialogAction>
+                                                            </div>
+                                                        </AlertDialogContent>
+                                                    </AlertDialog>
+                                                </>)}
                                             </div>
                                         </td>
                                     </tr>
@@ -0,0 +1,110 @@
+import { useState, useEffect } from "react"
+import { History, RotateCcw } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import {
+    AlertDialog,
+    AlertDialogAction,
+    AlertDialogCancel,
+    AlertDialogContent,
+    AlertDialogDescription,
+    AlertDialogTitle,
+} from "@/components/ui/alert-dialog"
+import type { BackupInfo, BackupPreview } from "@/types"
+import { ListBackups, PreviewBackup, RestoreBackup } from "../../../wailsjs/go/main/App"
+import { EventsOn } from "../../../wailsjs/runtime/runtime"
+
+function NameList({ label, names }: { label: string; names: string[] }) {
+    if (names.length === 0) return null
+    return (
+        <p className="text-[12px] text-fg-muted">
+            <span className="font-medium text-fg">{label}:</span> {names.join(", ")}
+        </p>
+    )
+}
+
+export default function BackupsSection() {
+    const [backups, setBackups] = useState<BackupInfo[]>([])
+    const [preview, setPreview] = useState<BackupPreview | null>(null)
+    const [error, setError] = useState("")
+
+    const loadBackups = () => {
+        ListBackups().then(setBackups).catch((err) => console.error("Error loading backups:", err))
+    }
+
+    // A destructive edit anywhere takes a new snapshot first.
+    useEffect(() => {
+        loadBackups()
+        return EventsOn("shortcuts:changed", loadBackups)
+    }, [])
+
+    const openPreview = async (id: string) => {
+        setError("")
+        try {
+            setPreview(await PreviewBackup(id))
+        } catch (err) {
+            setError(String(err))
+        }
+    }
+
+    const handleRestore = async () => {
+        if (!preview) return
+        try {
+            await RestoreBackup(preview.id)
+        } catch (err) {
+            setError(String(err))
+        }
+        setPreview(null)
+    }
+
+    const unchanged = preview && preview.added.length + preview.removed.length + preview.changed.length === 0
+
+    return (
+        <>
+            {backups.length === 0 ? (
+                <p className="px-5 py-4 text-[12px] text-fg-faint">
+                    No backups yet. A snapshot is taken automatically before shortcuts are deleted or overwritten.
+                </p>
+            ) : (
+                backups.map((b) => (
+                    <div key={b.id} className="flex items-center gap-3 border-b border-edge px-5 py-3 last:border-b-0">
+                        <History className="h-4 w-4 shrink-0 text-fg-faint" />
+                        <div className="min-w-0 flex-1">
+                            <p className="text-[13px] font-medium text-fg">{new Date(b.createdAt).toLocaleString()}</p>
+                            <p className="truncate text-[11px] text-fg-faint">
+                                {b.reason || "Snapshot"} · {b.shortcutCount} shortcut{b.shortcutCount === 1 ? "" : "s"}
+                            </p>
+                        </div>
+                        <Button variant="outline" size="sm" onClick={() => openPreview(b.id)}>
+                            <RotateCcw className="h-4 w-4" />
+                            Restore
+                        </Button>
+                    </div>
+                ))
+            )}
+            {error && <p className="px-5 pb-4 text-[12px] text-danger">{error}</p>}
+
+            <AlertDialog open={preview !== null} onOpenChange={(open) => !open && setPreview(null)}>
+                <AlertDialogContent>
+                    <AlertDialogTitle>Restore Backup</AlertDialogTitle>
+                    <AlertDialogDescription>
+                        Replace your shortcuts with the {preview?.shortcutCount} from{" "}
+                        <span className="font-semibold text-fg">{preview && new Date(preview.createdAt).toLocaleString()}</span>?
+                        The current library is backed up first.
+                    </AlertDialogDescription>
+                    {preview && (
+                        <div className="max-h-48 space-y-1 overflow-y-auto">
+                            {unchanged && <p className="text-[12px] text-fg-faint">This backup matches your current shortcuts.</p>}
+                            <NameList label="Brings back" names={preview.added} />
+                            <NameList label="Reverts" names={preview.changed} />
+                            <NameList label="Removes" names={preview.removed} />
+                        </div>
+                    )}
+                    <div className="mt-2 flex justify-end gap-2">
+                        <AlertDialogCancel>Cancel</AlertDialogCancel>
+                        <AlertDialogAction onClick={handleRestore}>Restore</AlertDialogAction>
+                    </div>
+                </AlertDialogContent>
+            </AlertDialog>
+        </>
+    )
+}
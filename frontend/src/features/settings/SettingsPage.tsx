@@ -1,5 +1,5 @@
 ﻿import { useState, useEffect } from "react"
-import { Download, Upload, ExternalLink, Terminal, FolderOpen, Plus, Trash2, Power } from "lucide-react"
+import { Download, Upload, Archive, ArchiveRestore, ExternalLink, Terminal, FolderOpen, Plus, Trash2, Power, Plug, Link } from "lucide-react"
 import { Button } from "@/components/ui/button"
 import { Input } from "@/components/ui/input"
 import { Card, CardContent } from "@/components/ui/card"
@@ -15,20 +15,34 @@ import {
     AlertDialogTitle,
     AlertDialogTrigger,
 } from "@/components/ui/alert-dialog"
-import { ImportShortcuts, ExportShortcuts, SetPreferredTerminal, SetStartOnBoot, GetStartOnBoot, AddSavedDirectory, RemoveSavedDirectory, SelectDirectory } from "../../../wailsjs/go/main/App"
+import { SelectImportFile, PreviewImport, ApplyImport, ShellProfiles, ExportBundle, ImportBundle, ExportShell, ExportShortcuts, SetPreferredTerminal, GetTerminalLaunchers, SaveCustomTerminal, RemoveCustomTerminal, SetStartOnBoot, GetStartOnBoot, SetURLHandler, GetURLHandler, SetEnableAPI, GetAPIInfo, AddSavedDirectory, RemoveSavedDirectory, SelectDirectory } from "../../../wailsjs/go/main/App"
 import { useVersion } from "@/contexts/VersionContext"
 import { useAppConfig } from "@/contexts/VersionContext"
 import { formatReleaseDate } from "@/lib/dateHelpers"
-import type { SavedDir } from "@/types"
+import BackupsSection from "./BackupsSection"
+import TrashSection from "./TrashSection"
+import ImportPreviewDialog from "@/components/ImportPreviewDialog"
+import type { APIInfo, ImportPlan, SavedDir, TerminalLauncher } from "@/types"
 
-const TERMINAL_OPTIONS = [
-    { value: "auto", label: "Auto-detect" },
-    { value: "wt", label: "Windows Terminal (wt)" },
+const SHELL_EXPORT_OPTIONS = [
+    { value: "bash", label: "bash / zsh aliases" },
+    { value: "fish", label: "fish functions" },
+    { value: "powershell", label: "PowerShell profile" },
+    { value: "scripts", label: "Folder of scripts" },
+]
+
+const SHELL_OPTIONS = [
+    { value: "default", label: "Default shell" },
+    { value: "posix", label: "POSIX (bash)" },
     { value: "powershell", label: "PowerShell" },
     { value: "cmd", label: "Command Prompt (cmd)" },
-    { value: "bash", label: "Bash / Unix terminal" },
 ]
 
+// Shows an argv template the way it would be typed, quoting arguments with spaces.
+function formatTemplate(args: string[]) {
+    return args.map((a) => (/\s/.test(a) ? `"${a}"` : a)).join(" ")
+}
+
 function SectionLabel({ children }: { children: React.ReactNode }) {
     return (
         <h3 className="px-1 pt-6 pb-2 text-[11px] font-semibold tracking-wider text-fg-faint uppercase">
@@ -42,11 +56,31 @@ export default function SettingsPage() {
     const { config, refreshConfig } = useAppConfig()
 
     const [startOnBoot, setStartOnBoot] = useState(false)
+    const [urlHandler, setUrlHandler] = useState(false)
     const [newDirName, setNewDirName] = useState("")
     const [newDirPath, setNewDirPath] = useState("")
+    const [importPlan, setImportPlan] = useState<ImportPlan | null>(null)
+    const [bundleHistory, setBundleHistory] = useState(false)
+    const [shellFormat, setShellFormat] = useState("bash")
+    const [profiles, setProfiles] = useState<string[]>([])
+    const [profile, setProfile] = useState("")
+    const [apiInfo, setApiInfo] = useState<APIInfo | null>(null)
+    const [terminals, setTerminals] = useState<TerminalLauncher[]>([])
+    const [newTermName, setNewTermName] = useState("")
+    const [newTermTemplate, setNewTermTemplate] = useState("")
+    const [newTermShell, setNewTermShell] = useState("default")
 
     useEffect(() => {
         GetStartOnBoot().then(setStartOnBoot).catch(console.error)
+        GetURLHandler().then(setUrlHandler).catch(console.error)
+        GetAPIInfo().then(setApiInfo).catch(console.error)
+        GetTerminalLaunchers().then(setTerminals).catch(console.error)
+        ShellProfiles()
+            .then((found) => {
+                setProfiles(found ?? [])
+                if (found?.length) setProfile(found[0])
+            })
+            .catch(console.error)
     }, [])
 
     const handleTerminalChange = async (value: string) => {
@@ -54,11 +88,50 @@ export default function SettingsPage() {
         await refreshConfig()
     }
 
+    const handleAddTerminal = async () => {
+        if (!newTermName.trim() || !newTermTemplate.trim()) return
+        try {
+            await SaveCustomTerminal(newTermName.trim(), "", newTermTemplate, newTermShell === "default" ? "" : newTermShell)
+        } catch (err) {
+            alert(`Could not add the terminal: ${err}`)
+            return
+        }
+        setNewTermName("")
+        setNewTermTemplate("")
+        setNewTermShell("default")
+        setTerminals(await GetTerminalLaunchers())
+    }
+
+    const handleRemoveTerminal = async (name: string) => {
+        await RemoveCustomTerminal(name)
+        setTerminals(await GetTerminalLaunchers())
+        await refreshConfig()
+    }
+
     const handleBootToggle = async (enabled: boolean) => {
         await SetStartOnBoot(enabled)
         setStartOnBoot(enabled)
     }
 
+    const handleUrlToggle = async (enabled: boolean) => {
+        try {
+            await SetURLHandler(enabled)
+        } catch (err) {
+            alert(`Could not update the yagui:// link handler: ${err}`)
+        }
+        setUrlHandler(await GetURLHandler())
+    }
+
+    const handleApiToggle = async (enabled: boolean) => {
+        try {
+            await SetEnableAPI(enabled)
+        } catch (err) {
+            alert(`Could not start the control API: ${err}`)
+        }
+        setApiInfo(await GetAPIInfo())
+        await refreshConfig()
+    }
+
     const handleBrowseDir = async () => {
         const path = await SelectDirectory()
         if (path) setNewDirPath(path)
@@ -77,10 +150,79 @@ export default function SettingsPage() {
         await refreshConfig()
     }
 
+    const handleImport = async () => {
+        try {
+            const path = await SelectImportFile()
+            if (path) setImportPlan(await PreviewImport(path))
+        } catch (err) {
+            alert(`Could not read the file: ${err}`)
+        }
+    }
+
+    const handleImportProfile = async () => {
+        if (!profile) return handleImport()
+        try {
+            setImportPlan(await PreviewImport(profile))
+        } catch (err) {
+            alert(`Could not read the file: ${err}`)
+        }
+    }
+
+    const handleApplyImport = async () => {
+        if (!importPlan) return
+        try {
+            const r = await ApplyImport(importPlan)
+            alert(`Imported: ${r.added} added, ${r.updated} updated, ${r.renamed} renamed, ${r.skipped} skipped.`)
+        } catch (err) {
+            alert(`Import failed: ${err}`)
+        }
+        setImportPlan(null)
+    }
+
+    const handleExportShell = async () => {
+        try {
+            const r = await ExportShell(shellFormat)
+            if (r.skipped?.length) alert(`Exported to ${r.path}, except:\n${r.skipped.join("\n")}`)
+        } catch (err) {
+            alert(`Export failed: ${err}`)
+        }
+    }
+
+    const handleExportBundle = async () => {
+        try {
+            await ExportBundle(bundleHistory)
+        } catch (err) {
+            alert(`Export failed: ${err}`)
+        }
+    }
+
+    const handleImportBundle = async () => {
+        try {
+            const path = await SelectImportFile()
+            if (!path) return
+            const r = await ImportBundle(path)
+            const parts = [`${r.added} shortcuts added, ${r.updated} updated, ${r.unchanged} unchanged`]
+            if (r.config) parts.push(`settings and ${r.savedDirectories} saved directories`)
+            if (r.history) parts.push(`${r.history} history entries`)
+            let message = `Restored ${parts.join("; ")}.`
+            if (r.rejectedTerminals) message += `\n${r.rejectedTerminals} invalid custom terminals were skipped.`
+            alert(message)
+            await refreshConfig()
+        } catch (err) {
+            alert(`Import failed: ${err}`)
+        }
+    }
+
     const savedDirs: SavedDir[] = config.savedDirectories ?? []
 
     return (
         <div className="flex h-full flex-col overflow-y-auto p-4">
+            <ImportPreviewDialog
+                plan={importPlan}
+                onChange={(i, item) => setImportPlan((prev) => prev && { ...prev, items: prev.items.map((it, j) => (j === i ? item : it)) })}
+                onConfirm={handleApplyImport}
+                onCancel={() => setImportPlan(null)}
+            />
             <div className="mx-auto w-full max-w-3xl space-y-3 pb-12">
                 <SectionLabel>Terminal &amp; Startup</SectionLabel>
                 <Card>
@@ -98,8 +240,9 @@ export default function SettingsPage() {
                                     <SelectValue />
                                 </SelectTrigger>
                                 <SelectContent>
-                                    {TERMINAL_OPTIONS.map((opt) => (
-                                        <SelectItem key={opt.value} value={opt.value}>{opt.label}</SelectItem>
+                                    <SelectItem value="auto">Auto-detect</SelectItem>
+                                    {terminals.map((t) => (
+                                        <SelectItem key={t.name} value={t.name}>{t.label || t.name}</SelectItem>
                                     ))}
                                 </SelectContent>
                             </Select>
@@ -115,6 +258,97 @@ export default function SettingsPage() {
                             </div>
                             <Switch checked={startOnBoot} onCheckedChange={handleBootToggle} aria-label="Start on boot" />
                         </div>
+
+                        <div className="flex items-center justify-between gap-6 border-t border-edge px-5 py-4">
+                            <div className="min-w-0">
+                                <p className="flex items-center gap-2 text-[13px] font-medium text-fg">
+                                    <Link className="h-4 w-4 shrink-0 text-fg-faint" />
+                                    Open yagui:// Links
+                                </p>
+                                <p className="mt-0.5 text-[12px] text-fg-faint">Run shortcuts from links in docs and READMEs, after you confirm</p>
+                            </div>
+                            <Switch checked={urlHandler} onCheckedChange={handleUrlToggle} aria-label="Open yagui:// links" />
+                        </div>
+
+                        <div className="border-t border-edge px-5 py-4">
+                            <div className="flex items-center justify-between gap-6">
+                                <div className="min-w-0">
+                                    <p className="flex items-center gap-2 text-[13px] font-medium text-fg">
+                                        <Plug className="h-4 w-4 shrink-0 text-fg-faint" />
+                                        Control API
+                                    </p>
+                                    <p className="mt-0.5 text-[12px] text-fg-faint">Let editor plugins and launchers list and run shortcuts</p>
+                                </div>
+                                <Switch checked={config.enableApi ?? false} onCheckedChange={handleApiToggle} aria-label="Control API" />
+                            </div>
+                            {apiInfo?.address && (
+                                <div className="mt-3 space-y-1 text-[11px] text-fg-faint">
+                                    <p>Listening on <span className="mono-cell text-fg-muted">{apiInfo.address}</span></p>
+                                    <p>Token in <span className="mono-cell text-fg-muted">{apiInfo.tokenFile}</span></p>
+                                </div>
+                            )}
+                        </div>
+                    </CardContent>
+                </Card>
+
+                <SectionLabel>Custom Terminals</SectionLabel>
+                <Card>
+                    <CardContent className="p-0">
+                        {terminals.filter((t) => !t.builtIn).map((t) => (
+                            <div key={t.name} className="flex items-center gap-3 border-b border-edge px-5 py-3">
+                                <div className="min-w-0 flex-1">
+                                    <p className="flex items-center gap-2 text-[13px] font-medium text-fg">
+                                        {t.label || t.name}
+                                        {t.shell && <Badge variant="secondary">{t.shell}</Badge>}
+                                    </p>
+                                    <p className="mono-cell truncate text-[11px] text-fg-faint">{formatTemplate(t.args)}</p>
+                                </div>
+                                <Button variant="danger-ghost" size="icon-sm" title={`Remove ${t.name}`} onClick={() => handleRemoveTerminal(t.name)}>
+                                    <Trash2 className="h-4 w-4" />
+                                </Button>
+                            </div>
+                        ))}
+
+                        <div className="px-5 py-4">
+                            <p className="mb-2 text-[12px] font-medium text-fg-muted">Add Terminal</p>
+                            <p className="mb-2 text-[11px] text-fg-faint">
+                                The command line to start it with. <span className="mono-cell">{"{command}"}</span> is the shortcut's command,{" "}
+                                <span className="mono-cell">{"{shell}"}</span> the shell to run it in and <span className="mono-cell">{"{dir}"}</span> the directory,
+                                e.g. <span className="mono-cell">{'kitty --directory {dir} {shell} -c "{command}; exec {shell}"'}</span>
+                            </p>
+                            <div className="grid grid-cols-1 gap-2 md:grid-cols-[10rem_1fr_10rem]">
+                                <Input
+                                    placeholder="Name"
+                                    value={newTermName}
+                                    onChange={(e) => setNewTermName(e.target.value)}
+                                />
+                                <Input
+                                    placeholder="Command line"
+                                    value={newTermTemplate}
+                                    onChange={(e) => setNewTermTemplate(e.target.value)}
+                                    className="mono-cell"
+                                />
+                                <Select value={newTermShell} onValueChange={setNewTermShell}>
+                                    <SelectTrigger>
+                                        <SelectValue />
+                                    </SelectTrigger>
+                                    <SelectContent>
+                                        {SHELL_OPTIONS.map((opt) => (
+                                            <SelectItem key={opt.value} value={opt.value}>{opt.label}</SelectItem>
+                                        ))}
+                                    </SelectContent>
+                                </Select>
+                            </div>
+                            <Button
+                                onClick={handleAddTerminal}
+                                disabled={!newTermName.trim() || !newTermTemplate.trim()}
+                                size="sm"
+                                className="mt-3"
+                            >
+                                <Plus className="h-4 w-4" />
+                                Add Terminal
+                            </Button>
+                        </div>
                     </CardContent>
                 </Card>
 
@@ -192,7 +426,7 @@ export default function SettingsPage() {
                                 <Download className="h-4 w-4 text-accent-soft" />
                                 Export Shortcuts
                             </Button>
-                            <Button variant="outline" className="justify-start py-5" onClick={() => ImportShortcuts()}>
+                            <Button variant="outline" className="justify-start py-5" onClick={handleImport}>
                                 <Upload className="h-4 w-4 text-accent-soft" />
                                 Import Shortcuts
                             </Button>
@@ -200,6 +434,91 @@ export default function SettingsPage() {
                         <p className="px-5 pb-5 text-[11px] text-fg-faint">
                             Shortcuts live in your <span className="mono-cell text-fg-muted">ya</span> CLI config — export merges back, import merges from a file.
                         </p>
+                        <div className="grid grid-cols-1 gap-3 border-t border-edge p-5 sm:grid-cols-2">
+                            <Button variant="outline" className="justify-start py-5" onClick={handleExportBundle}>
+                                <Archive className="h-4 w-4 text-accent-soft" />
+                                Export Full Backup
+                            </Button>
+                            <Button variant="outline" className="justify-start py-5" onClick={handleImportBundle}>
+                                <ArchiveRestore className="h-4 w-4 text-accent-soft" />
+                                Import Full Backup
+                            </Button>
+                        </div>
+                        <div className="flex items-center justify-between gap-6 px-5 pb-5">
+                            <p className="text-[11px] text-fg-faint">
+                                A full backup also keeps descriptions, tags, pins, saved directories and settings — use it to move to another machine.
+                            </p>
+                            <label className="flex shrink-0 items-center gap-2 text-[12px] text-fg-muted">
+                                Include run history
+                                <Switch checked={bundleHistory} onCheckedChange={setBundleHistory} aria-label="Include run history" />
+                            </label>
+                        </div>
+                        <div className="flex items-center justify-between gap-6 border-t border-edge px-5 py-4">
+                            <div className="min-w-0">
+                                <p className="flex items-center gap-2 text-[13px] font-medium text-fg">
+                                    <Terminal className="h-4 w-4 shrink-0 text-fg-faint" />
+                                    Export for Shells
+                                </p>
+                                <p className="mt-0.5 text-[12px] text-fg-faint">Use your shortcuts where neither Ya nor Ya GUI is installed</p>
+                            </div>
+                            <div className="flex shrink-0 gap-2">
+                                <Select value={shellFormat} onValueChange={setShellFormat}>
+                                    <SelectTrigger className="w-44">
+                                        <SelectValue />
+                                    </SelectTrigger>
+                                    <SelectContent>
+                                        {SHELL_EXPORT_OPTIONS.map((opt) => (
+                                            <SelectItem key={opt.value} value={opt.value}>{opt.label}</SelectItem>
+                                        ))}
+                                    </SelectContent>
+                                </Select>
+                                <Button variant="outline" onClick={handleExportShell}>
+                                    <Download className="h-4 w-4 text-accent-soft" />
+                                    Export
+                                </Button>
+                            </div>
+                        </div>
+                        <div className="flex items-center justify-between gap-6 border-t border-edge px-5 py-4">
+                            <div className="min-w-0">
+                                <p className="flex items-center gap-2 text-[13px] font-medium text-fg">
+                                    <Terminal className="h-4 w-4 shrink-0 text-fg-faint" />
+                                    Import from Shell
+                                </p>
+                                <p className="mt-0.5 text-[12px] text-fg-faint">Bring in aliases and simple functions from a shell profile</p>
+                            </div>
+                            <div className="flex shrink-0 gap-2">
+                                {profiles.length > 0 && (
+                                    <Select value={profile} onValueChange={setProfile}>
+                                        <SelectTrigger className="w-44">
+                                            <SelectValue />
+                                        </SelectTrigger>
+                                        <SelectContent>
+                                            {profiles.map((p) => (
+                                                <SelectItem key={p} value={p}>{p.split(/[\\/]/).pop()}</SelectItem>
+                                            ))}
+                                        </SelectContent>
+                                    </Select>
+                                )}
+                                <Button variant="outline" onClick={handleImportProfile}>
+                                    <Upload className="h-4 w-4 text-accent-soft" />
+                                    {profiles.length > 0 ? "Import" : "Choose File"}
+                                </Button>
+                            </div>
+                        </div>
+                    </CardContent>
+                </Card>
+
+                <SectionLabel>Trash</SectionLabel>
+                <Card>
+                    <CardContent className="p-0">
+                        <TrashSection />
+                    </CardContent>
+                </Card>
+
+                <SectionLabel>Backups</SectionLabel>
+                <Card>
+                    <CardContent className="p-0">
+                        <BackupsSection />
                     </CardContent>
                 </Card>
 
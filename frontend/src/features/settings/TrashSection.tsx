@@ -0,0 +1,121 @@
+import { useState, useEffect } from "react"
+import { RotateCcw, Trash2 } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import {
+    AlertDialog,
+    AlertDialogAction,
+    AlertDialogCancel,
+    AlertDialogContent,
+    AlertDialogDescription,
+    AlertDialogTitle,
+    AlertDialogTrigger,
+} from "@/components/ui/alert-dialog"
+import { useAppConfig } from "@/contexts/VersionContext"
+import type { TrashEntry } from "@/types"
+import { ListTrash, RestoreFromTrash, EmptyTrash, SetTrashRetentionDays } from "../../../wailsjs/go/main/App"
+import { EventsOn } from "../../../wailsjs/runtime/runtime"
+
+const RETENTION_OPTIONS = [
+    { value: "7", label: "7 days" },
+    { value: "30", label: "30 days" },
+    { value: "90", label: "90 days" },
+    { value: "365", label: "1 year" },
+]
+
+export default function TrashSection() {
+    const { config, refreshConfig } = useAppConfig()
+    const [entries, setEntries] = useState<TrashEntry[]>([])
+
+    const loadTrash = () => {
+        ListTrash().then(setEntries).catch((err) => console.error("Error loading trash:", err))
+    }
+
+    // Deleting, undoing or restoring anywhere changes the trash.
+    useEffect(() => {
+        loadTrash()
+        return EventsOn("shortcuts:changed", loadTrash)
+    }, [])
+
+    const handleRestore = async (name: string) => {
+        try {
+            const restored = await RestoreFromTrash(name)
+            if (restored !== name) alert(`"${name}" already exists, so it was restored as "${restored}".`)
+        } catch (err) {
+            alert(String(err))
+        }
+        loadTrash()
+    }
+
+    const handleEmpty = async () => {
+        await EmptyTrash()
+        loadTrash()
+    }
+
+    const handleRetentionChange = async (value: string) => {
+        await SetTrashRetentionDays(Number(value))
+        await refreshConfig()
+        loadTrash()
+    }
+
+    return (
+        <>
+            <div className="flex items-center justify-between gap-6 border-b border-edge px-5 py-4">
+                <div className="min-w-0">
+                    <p className="text-[13px] font-medium text-fg">Keep deleted shortcuts for</p>
+                    <p className="mt-0.5 text-[12px] text-fg-faint">Older entries are removed permanently</p>
+                </div>
+                <Select value={String(config.trashRetentionDays || 30)} onValueChange={handleRetentionChange}>
+                    <SelectTrigger className="w-36">
+                        <SelectValue />
+                    </SelectTrigger>
+                    <SelectContent>
+                        {RETENTION_OPTIONS.map((opt) => (
+                            <SelectItem key={opt.value} value={opt.value}>{opt.label}</SelectItem>
+                        ))}
+                    </SelectContent>
+                </Select>
+            </div>
+
+            {entries.length === 0 ? (
+                <p className="px-5 py-4 text-[12px] text-fg-faint">The trash is empty.</p>
+            ) : (
+                <>
+                    {entries.map((e) => (
+                        <div key={`${e.name}-${e.deletedAt}`} className="flex items-center gap-3 border-b border-edge px-5 py-3">
+                            <div className="min-w-0 flex-1">
+                                <p className="text-[13px] font-medium text-fg">{e.name}</p>
+                                <p className="mono-cell truncate text-[11px] text-fg-faint">{e.shortcut.command}</p>
+                                <p className="text-[11px] text-fg-faint">Deleted {new Date(e.deletedAt).toLocaleString()}</p>
+                            </div>
+                            <Button variant="outline" size="sm" onClick={() => handleRestore(e.name)}>
+                                <RotateCcw className="h-4 w-4" />
+                                Restore
+                            </Button>
+                        </div>
+                    ))}
+                    <div className="px-5 py-4">
+                        <AlertDialog>
+                            <AlertDialogTrigger asChild>
+                                <Button variant="danger-ghost" size="sm">
+                                    <Trash2 className="h-4 w-4" />
+                                    Empty Trash
+                                </Button>
+                            </AlertDialogTrigger>
+                            <AlertDialogContent>
+                                <AlertDialogTitle>Empty Trash</AlertDialogTitle>
+                                <AlertDialogDescription>
+                                    Permanently delete {entries.length} shortcut{entries.length === 1 ? "" : "s"}? This cannot be undone.
+                                </AlertDialogDescription>
+                                <div className="mt-2 flex justify-end gap-2">
+                                    <AlertDialogCancel>Cancel</AlertDialogCancel>
+                                    <AlertDialogAction className="bg-danger-strong hover:bg-danger" onClick={handleEmpty}>Empty Trash</AlertDialogAction>
+                                </div>
+                            </AlertDialogContent>
+                        </AlertDialog>
+                    </div>
+                </>
+            )}
+        </>
+    )
+}
@@ -13,6 +13,7 @@ export function formatShortcuts(shortcuts: Record<string, ShortcutData>): Shortc
         pinned: data.pinned ?? false,
         runCount: data.runCount ?? 0,
         lastRun: data.lastRun ?? "",
+        source: data.source ?? "",
     }))
     return list.sort((a, b) => {
         if (a.pinned !== b.pinned) return a.pinned ? -1 : 1
@@ -53,18 +54,3 @@ export function collectAllTags(shortcuts: Shortcut[]): string[] {
     shortcuts.forEach((s) => s.tags.forEach((t) => set.add(t)))
     return Array.from(set).sort()
 }
-
-/**
- * Detects {variable} placeholders in a command string.
- */
-export function extractVariables(command: string): string[] {
-    const matches = command.match(/\{([^}]+)\}/g) ?? []
-    return [...new Set(matches.map((m) => m.slice(1, -1)))]
-}
-
-/**
- * Replaces {variable} placeholders with provided values.
- */
-export function substituteVariables(command: string, values: Record<string, string>): string {
-    return command.replace(/\{([^}]+)\}/g, (_, name) => values[name] ?? `{${name}}`)
-}
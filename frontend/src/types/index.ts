@@ -7,13 +7,32 @@ export interface ShortcutData {
     pinned?: boolean
     runCount?: number
     lastRun?: string
+    varHistory?: Record<string, string[]>  // recent values per placeholder, newest first
+    source?: string                        // .ya.json of a project shortcut; absent for the library
 }
 
 export interface AppConfig {
     defaultDir?: string
-    preferredTerminal?: string  // "auto" | "wt" | "powershell" | "cmd" | "bash"
+    preferredTerminal?: string  // "auto" or the name of a TerminalLauncher
     startOnBoot?: boolean
     savedDirectories?: SavedDir[]
+    trashRetentionDays?: number  // 0 / absent = 30 days
+    enableApi?: boolean
+    customTerminals?: TerminalLauncher[]
+    trustedProjectFiles?: TrustedProjectFile[]  // .ya.json files allowed to replace library shortcuts
+}
+
+export interface TrustedProjectFile {
+    path: string
+    sum: string     // SHA-256 of the trusted contents
+}
+
+export interface TerminalLauncher {
+    name: string
+    label?: string
+    args: string[]      // argv template with {shell}, {dir} and {command}
+    shell?: string      // "posix" | "powershell" | "cmd"; absent = platform default
+    builtIn?: boolean
 }
 
 export interface SavedDir {
@@ -22,10 +41,115 @@ export interface SavedDir {
 }
 
 export interface RunHistoryEntry {
+    id?: string
     shortcutName: string
     command: string
     directory: string
     timestamp: string
+    mode?: string       // "terminal" | "inline" | "pty" | "cli"
+    source?: string     // project file for shortcuts and tasks outside the library
+    terminal?: string
+    exitCode?: number   // only set for inline / pty / cli runs once finished
+    error?: string
+    durationMs?: number
+    outputTail?: string[]
+}
+
+export interface Placeholder {
+    name: string
+    type: string        // "text" | "choice"
+    default?: string
+    choices?: string[]
+    required: boolean
+}
+
+export interface ImportItem {
+    name: string
+    status: string          // "new" | "identical" | "changed" | "metadata"
+    incoming: ShortcutData
+    current?: ShortcutData
+    hasMeta: boolean        // source carries description / tags / pin
+    action: string          // "keep" | "theirs" | "rename"
+    renameTo?: string
+}
+
+export interface ImportPlan {
+    source: string
+    items: ImportItem[]
+    skipped?: string[]
+}
+
+export interface ImportResult {
+    added: number
+    updated: number
+    renamed: number
+    skipped: number
+}
+
+export interface ProjectTask {
+    name: string
+    command: string
+    description?: string
+    runner: string
+    source: string
+}
+
+export interface APIInfo {
+    address: string     // empty while the control API is off
+    tokenFile: string
+    pid: number
+}
+
+// A .ya.json that redefines library shortcuts but has not been trusted yet.
+export interface ProjectShadow {
+    file: string
+    sum: string         // contents the names were read from
+    names: string[]
+}
+
+export interface LaunchRequest {
+    name: string
+    dir?: string
+    values?: Record<string, string>
+    url?: string        // set for yagui:// links, which must be confirmed first
+    error?: string      // why the link cannot be used
+}
+
+export interface TrashEntry {
+    name: string
+    shortcut: ShortcutData
+    deletedAt: string
+}
+
+export interface BackupInfo {
+    id: string
+    createdAt: string
+    reason: string
+    shortcutCount: number
+}
+
+export interface BackupPreview extends BackupInfo {
+    shortcuts: Record<string, ShortcutData>
+    added: string[]     // in the backup but not the current library
+    removed: string[]   // in the current library but not the backup
+    changed: string[]
+}
+
+export interface ShortcutChange {
+    name: string
+    before?: ShortcutData   // absent when the shortcut was created
+    after?: ShortcutData    // absent when it was deleted
+}
+
+export interface JournalEntry {
+    time: string
+    op: string              // e.g. 'Delete "build"'
+    changes: ShortcutChange[]
+}
+
+export interface RecentChanges {
+    undo: JournalEntry[]    // newest first
+    redo: JournalEntry[]
 }
 
 /** Flat shortcut used in the UI, derived from the map key + ShortcutData value */
@@ -37,4 +161,5 @@ export interface Shortcut {
     pinned: boolean
     runCount: number
     lastRun: string
+    source: string
 }
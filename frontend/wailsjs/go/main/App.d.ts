@@ -6,38 +6,124 @@ export function AddSavedDirectory(arg1:string,arg2:string):Promise<void>;
 
 export function AddShortcut(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Record<string, utils.ShortcutData>>;
 
-export function ApplyShortcut(arg1:string,arg2:string,arg3:string):Promise<boolean>;
+export function ApplyImport(arg1:utils.ImportPlan):Promise<utils.ImportResult>;
+
+export function ApplyProjectTask(arg1:string,arg2:string,arg3:Record<string, string>):Promise<void>;
+
+export function ApplyShortcut(arg1:string,arg2:string,arg3:string,arg4:Record<string, string>):Promise<void>;
 
 export function ClearRunHistory():Promise<void>;
 
 export function CliExists(arg1:string):Promise<boolean>;
 
+export function CloseTerminalSession(arg1:string):Promise<void>;
+
+export function DiscoverProjectTasks(arg1:string):Promise<Array<utils.ProjectTask>>;
+
 export function DuplicateShortcut(arg1:string):Promise<Record<string, utils.ShortcutData>>;
 
+export function EmptyTrash():Promise<void>;
+
+export function ExportBundle(arg1:boolean):Promise<string>;
+
+export function ExportShell(arg1:string):Promise<utils.ShellExportResult>;
+
 export function ExportShortcuts():Promise<void>;
 
+export function GetAPIInfo():Promise<utils.APIInfo>;
+
 export function GetConfig():Promise<utils.AppConfig>;
 
+export function GetRecentChanges():Promise<utils.RecentChanges>;
+
 export function GetRunHistory():Promise<Array<utils.RunHistoryEntry>>;
 
 export function GetShortcuts():Promise<Record<string, utils.ShortcutData>>;
 
+export function GetShortcutsForDir(arg1:string):Promise<Record<string, utils.ShortcutData>>;
+
 export function GetStartOnBoot():Promise<boolean>;
 
+export function GetTerminalLaunchers():Promise<Array<utils.TerminalLauncher>>;
+
+export function GetURLHandler():Promise<boolean>;
+
+export function GetUntrustedProjectFiles(arg1:string):Promise<Array<utils.ProjectShadow>>;
+
+export function GetVariableHistory(arg1:string):Promise<Record<string, Array<string>>>;
+
 export function GetVersion():Promise<string>;
 
+export function ImportBundle(arg1:string):Promise<utils.BundleReport>;
+
 export function ImportShortcuts():Promise<void>;
 
+export function ListBackups():Promise<Array<utils.BackupInfo>>;
+
+export function ListRunningShortcuts():Promise<Array<utils.RunningProcess>>;
+
+export function ListTrash():Promise<Array<utils.TrashEntry>>;
+
+export function NewRunID():Promise<string>;
+
+export function ParseCommandVariables(arg1:string):Promise<Array<utils.Placeholder>>;
+
+export function ParseShortcutVariables(arg1:string):Promise<Array<utils.Placeholder>>;
+
+export function PreviewBackup(arg1:string):Promise<utils.BackupPreview>;
+
+export function PreviewImport(arg1:string):Promise<utils.ImportPlan>;
+
+export function PromoteProjectTask(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function Redo():Promise<utils.JournalEntry>;
+
+export function RemoveCustomTerminal(arg1:string):Promise<void>;
+
 export function RemoveSavedDirectory(arg1:string):Promise<void>;
 
 export function RemoveShortcut(arg1:string):Promise<void>;
 
+export function RenderCommand(arg1:string,arg2:Record<string, string>):Promise<string>;
+
+export function ResizeTerminalSession(arg1:string,arg2:number,arg3:number):Promise<void>;
+
+export function RestoreBackup(arg1:string):Promise<void>;
+
+export function RestoreFromTrash(arg1:string):Promise<string>;
+
+export function RunShortcut(arg1:string,arg2:string,arg3:string,arg4:string,arg5:Record<string, string>):Promise<string>;
+
+export function SaveCustomTerminal(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
 export function SelectDirectory():Promise<string>;
 
+export function SelectImportFile():Promise<string>;
+
+export function SetEnableAPI(arg1:boolean):Promise<void>;
+
 export function SetPreferredTerminal(arg1:string):Promise<void>;
 
 export function SetStartOnBoot(arg1:boolean):Promise<void>;
 
+export function SetTrashRetentionDays(arg1:number):Promise<void>;
+
+export function SetURLHandler(arg1:boolean):Promise<void>;
+
+export function ShellProfiles():Promise<Array<string>>;
+
+export function StartTerminalSession(arg1:string,arg2:string,arg3:string,arg4:string,arg5:Record<string, string>,arg6:number,arg7:number):Promise<string>;
+
+export function StopRun(arg1:string):Promise<void>;
+
+export function TakeLaunchRequests():Promise<Array<utils.LaunchRequest>>;
+
 export function TogglePinShortcut(arg1:string):Promise<void>;
 
+export function TrustProjectFile(arg1:string,arg2:string):Promise<void>;
+
+export function Undo():Promise<utils.JournalEntry>;
+
 export function UpdateShortcut(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<void>;
+
+export function WriteTerminalSession(arg1:string,arg2:string):Promise<void>;
@@ -10,8 +10,16 @@ export function AddShortcut(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['AddShortcut'](arg1, arg2, arg3, arg4);
 }
 
-export function ApplyShortcut(arg1, arg2, arg3) {
-  return window['go']['main']['App']['ApplyShortcut'](arg1, arg2, arg3);
+export function ApplyImport(arg1) {
+  return window['go']['main']['App']['ApplyImport'](arg1);
+}
+
+export function ApplyProjectTask(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ApplyProjectTask'](arg1, arg2, arg3);
+}
+
+export function ApplyShortcut(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ApplyShortcut'](arg1, arg2, arg3, arg4);
 }
 
 export function ClearRunHistory() {
@@ -22,18 +30,46 @@ export function CliExists(arg1) {
   return window['go']['main']['App']['CliExists'](arg1);
 }
 
+export function CloseTerminalSession(arg1) {
+  return window['go']['main']['App']['CloseTerminalSession'](arg1);
+}
+
+export function DiscoverProjectTasks(arg1) {
+  return window['go']['main']['App']['DiscoverProjectTasks'](arg1);
+}
+
 export function DuplicateShortcut(arg1) {
   return window['go']['main']['App']['DuplicateShortcut'](arg1);
 }
 
+export function EmptyTrash() {
+  return window['go']['main']['App']['EmptyTrash']();
+}
+
+export function ExportBundle(arg1) {
+  return window['go']['main']['App']['ExportBundle'](arg1);
+}
+
+export function ExportShell(arg1) {
+  return window['go']['main']['App']['ExportShell'](arg1);
+}
+
 export function ExportShortcuts() {
   return window['go']['main']['App']['ExportShortcuts']();
 }
 
+export function GetAPIInfo() {
+  return window['go']['main']['App']['GetAPIInfo']();
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
 
+export function GetRecentChanges() {
+  return window['go']['main']['App']['GetRecentChanges']();
+}
+
 export function GetRunHistory() {
   return window['go']['main']['App']['GetRunHistory']();
 }
@@ -42,18 +78,86 @@ export function GetShortcuts() {
   return window['go']['main']['App']['GetShortcuts']();
 }
 
+export function GetShortcutsForDir(arg1) {
+  return window['go']['main']['App']['GetShortcutsForDir'](arg1);
+}
+
 export function GetStartOnBoot() {
   return window['go']['main']['App']['GetStartOnBoot']();
 }
 
+export function GetTerminalLaunchers() {
+  return window['go']['main']['App']['GetTerminalLaunchers']();
+}
+
+export function GetURLHandler() {
+  return window['go']['main']['App']['GetURLHandler']();
+}
+
+export function GetUntrustedProjectFiles(arg1) {
+  return window['go']['main']['App']['GetUntrustedProjectFiles'](arg1);
+}
+
+export function GetVariableHistory(arg1) {
+  return window['go']['main']['App']['GetVariableHistory'](arg1);
+}
+
 export function GetVersion() {
   return window['go']['main']['App']['GetVersion']();
 }
 
+export function ImportBundle(arg1) {
+  return window['go']['main']['App']['ImportBundle'](arg1);
+}
+
 export function ImportShortcuts() {
   return window['go']['main']['App']['ImportShortcuts']();
 }
 
+export function ListBackups() {
+  return window['go']['main']['App']['ListBackups']();
+}
+
+export function ListRunningShortcuts() {
+  return window['go']['main']['App']['ListRunningShortcuts']();
+}
+
+export function ListTrash() {
+  return window['go']['main']['App']['ListTrash']();
+}
+
+export function NewRunID() {
+  return window['go']['main']['App']['NewRunID']();
+}
+
+export function ParseCommandVariables(arg1) {
+  return window['go']['main']['App']['ParseCommandVariables'](arg1);
+}
+
+export function ParseShortcutVariables(arg1) {
+  return window['go']['main']['App']['ParseShortcutVariables'](arg1);
+}
+
+export function PreviewBackup(arg1) {
+  return window['go']['main']['App']['PreviewBackup'](arg1);
+}
+
+export function PreviewImport(arg1) {
+  return window['go']['main']['App']['PreviewImport'](arg1);
+}
+
+export function PromoteProjectTask(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PromoteProjectTask'](arg1, arg2, arg3);
+}
+
+export function Redo() {
+  return window['go']['main']['App']['Redo']();
+}
+
+export function RemoveCustomTerminal(arg1) {
+  return window['go']['main']['App']['RemoveCustomTerminal'](arg1);
+}
+
 export function RemoveSavedDirectory(arg1) {
   return window['go']['main']['App']['RemoveSavedDirectory'](arg1);
 }
@@ -62,10 +166,42 @@ export function RemoveShortcut(arg1) {
   return window['go']['main']['App']['RemoveShortcut'](arg1);
 }
 
+export function RenderCommand(arg1, arg2) {
+  return window['go']['main']['App']['RenderCommand'](arg1, arg2);
+}
+
+export function ResizeTerminalSession(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ResizeTerminalSession'](arg1, arg2, arg3);
+}
+
+export function RestoreBackup(arg1) {
+  return window['go']['main']['App']['RestoreBackup'](arg1);
+}
+
+export function RestoreFromTrash(arg1) {
+  return window['go']['main']['App']['RestoreFromTrash'](arg1);
+}
+
+export function RunShortcut(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['RunShortcut'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function SaveCustomTerminal(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SaveCustomTerminal'](arg1, arg2, arg3, arg4);
+}
+
 export function SelectDirectory() {
   return window['go']['main']['App']['SelectDirectory']();
 }
 
+export function SelectImportFile() {
+  return window['go']['main']['App']['SelectImportFile']();
+}
+
+export function SetEnableAPI(arg1) {
+  return window['go']['main']['App']['SetEnableAPI'](arg1);
+}
+
 export function SetPreferredTerminal(arg1) {
   return window['go']['main']['App']['SetPreferredTerminal'](arg1);
 }
@@ -74,10 +210,46 @@ export function SetStartOnBoot(arg1) {
   return window['go']['main']['App']['SetStartOnBoot'](arg1);
 }
 
+export function SetTrashRetentionDays(arg1) {
+  return window['go']['main']['App']['SetTrashRetentionDays'](arg1);
+}
+
+export function SetURLHandler(arg1) {
+  return window['go']['main']['App']['SetURLHandler'](arg1);
+}
+
+export function ShellProfiles() {
+  return window['go']['main']['App']['ShellProfiles']();
+}
+
+export function StartTerminalSession(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['StartTerminalSession'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function StopRun(arg1) {
+  return window['go']['main']['App']['StopRun'](arg1);
+}
+
+export function TakeLaunchRequests() {
+  return window['go']['main']['App']['TakeLaunchRequests']();
+}
+
 export function TogglePinShortcut(arg1) {
   return window['go']['main']['App']['TogglePinShortcut'](arg1);
 }
 
+export function TrustProjectFile(arg1, arg2) {
+  return window['go']['main']['App']['TrustProjectFile'](arg1, arg2);
+}
+
+export function Undo() {
+  return window['go']['main']['App']['Undo']();
+}
+
 export function UpdateShortcut(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['UpdateShortcut'](arg1, arg2, arg3, arg4, arg5);
 }
+
+export function WriteTerminalSession(arg1, arg2) {
+  return window['go']['main']['App']['WriteTerminalSession'](arg1, arg2);
+}
@@ -1,5 +1,55 @@
 export namespace utils {
 	
+	export class APIInfo {
+	    address: string;
+	    tokenFile: string;
+	    pid: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new APIInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.address = source["address"];
+	        this.tokenFile = source["tokenFile"];
+	        this.pid = source["pid"];
+	    }
+	}
+	export class TrustedProjectFile {
+	    path: string;
+	    sum: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrustedProjectFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.sum = source["sum"];
+	    }
+	}
+	export class TerminalLauncher {
+	    name: string;
+	    label?: string;
+	    args: string[];
+	    shell?: string;
+	    builtIn?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TerminalLauncher(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.args = source["args"];
+	        this.shell = source["shell"];
+	        this.builtIn = source["builtIn"];
+	    }
+	}
 	export class SavedDir {
 	    name: string;
 	    path: string;
@@ -19,6 +69,10 @@ export namespace utils {
 	    preferredTerminal?: string;
 	    startOnBoot?: boolean;
 	    savedDirectories?: SavedDir[];
+	    trashRetentionDays?: number;
+	    enableApi?: boolean;
+	    customTerminals?: TerminalLauncher[];
+	    trustedProjectFiles?: TrustedProjectFile[];
 	
 	    static createFrom(source: any = {}) {
 	        return new AppConfig(source);
@@ -30,6 +84,396 @@ export namespace utils {
 	        this.preferredTerminal = source["preferredTerminal"];
 	        this.startOnBoot = source["startOnBoot"];
 	        this.savedDirectories = this.convertValues(source["savedDirectories"], SavedDir);
+	        this.trashRetentionDays = source["trashRetentionDays"];
+	        this.enableApi = source["enableApi"];
+	        this.customTerminals = this.convertValues(source["customTerminals"], TerminalLauncher);
+	        this.trustedProjectFiles = this.convertValues(source["trustedProjectFiles"], TrustedProjectFile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupInfo {
+	    id: string;
+	    createdAt: string;
+	    reason: string;
+	    shortcutCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.createdAt = source["createdAt"];
+	        this.reason = source["reason"];
+	        this.shortcutCount = source["shortcutCount"];
+	    }
+	}
+	export class ShortcutData {
+	    command: string;
+	    description?: string;
+	    tags?: string[];
+	    pinned?: boolean;
+	    runCount?: number;
+	    lastRun?: string;
+	    varHistory?: Record<string, Array<string>>;
+	    source?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ShortcutData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.description = source["description"];
+	        this.tags = source["tags"];
+	        this.pinned = source["pinned"];
+	        this.runCount = source["runCount"];
+	        this.lastRun = source["lastRun"];
+	        this.varHistory = source["varHistory"];
+	        this.source = source["source"];
+	    }
+	}
+	export class BackupPreview {
+	    id: string;
+	    createdAt: string;
+	    reason: string;
+	    shortcutCount: number;
+	    shortcuts: Record<string, ShortcutData>;
+	    added: string[];
+	    removed: string[];
+	    changed: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.createdAt = source["createdAt"];
+	        this.reason = source["reason"];
+	        this.shortcutCount = source["shortcutCount"];
+	        this.shortcuts = this.convertValues(source["shortcuts"], ShortcutData, true);
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	        this.changed = source["changed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BundleReport {
+	    added: number;
+	    updated: number;
+	    unchanged: number;
+	    config: boolean;
+	    savedDirectories: number;
+	    history: number;
+	    rejectedTerminals: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BundleReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.updated = source["updated"];
+	        this.unchanged = source["unchanged"];
+	        this.config = source["config"];
+	        this.savedDirectories = source["savedDirectories"];
+	        this.history = source["history"];
+	        this.rejectedTerminals = source["rejectedTerminals"];
+	    }
+	}
+	export class ImportItem {
+	    name: string;
+	    status: string;
+	    incoming: ShortcutData;
+	    current?: ShortcutData;
+	    hasMeta: boolean;
+	    action: string;
+	    renameTo?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.incoming = this.convertValues(source["incoming"], ShortcutData);
+	        this.current = this.convertValues(source["current"], ShortcutData);
+	        this.hasMeta = source["hasMeta"];
+	        this.action = source["action"];
+	        this.renameTo = source["renameTo"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportPlan {
+	    source: string;
+	    items: ImportItem[];
+	    skipped?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.items = this.convertValues(source["items"], ImportItem);
+	        this.skipped = source["skipped"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportResult {
+	    added: number;
+	    updated: number;
+	    renamed: number;
+	    skipped: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.updated = source["updated"];
+	        this.renamed = source["renamed"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	export class ShortcutChange {
+	    name: string;
+	    before?: ShortcutData;
+	    after?: ShortcutData;
+	    trashed?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ShortcutChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.before = this.convertValues(source["before"], ShortcutData);
+	        this.after = this.convertValues(source["after"], ShortcutData);
+	        this.trashed = source["trashed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JournalEntry {
+	    time: string;
+	    op: string;
+	    changes: ShortcutChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new JournalEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.op = source["op"];
+	        this.changes = this.convertValues(source["changes"], ShortcutChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LaunchRequest {
+	    name: string;
+	    dir?: string;
+	    values?: Record<string, string>;
+	    url?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LaunchRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.dir = source["dir"];
+	        this.values = source["values"];
+	        this.url = source["url"];
+	        this.error = source["error"];
+	    }
+	}
+	export class Placeholder {
+	    name: string;
+	    type: string;
+	    default?: string;
+	    choices?: string[];
+	    required: boolean;
+	    raw?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Placeholder(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.default = source["default"];
+	        this.choices = source["choices"];
+	        this.required = source["required"];
+	        this.raw = source["raw"];
+	    }
+	}
+	export class ProjectShadow {
+	    file: string;
+	    sum: string;
+	    names: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectShadow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.sum = source["sum"];
+	        this.names = source["names"];
+	    }
+	}
+	export class ProjectTask {
+	    name: string;
+	    command: string;
+	    description?: string;
+	    runner: string;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.command = source["command"];
+	        this.description = source["description"];
+	        this.runner = source["runner"];
+	        this.source = source["source"];
+	    }
+	}
+	export class RecentChanges {
+	    undo: JournalEntry[];
+	    redo: JournalEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RecentChanges(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.undo = this.convertValues(source["undo"], JournalEntry);
+	        this.redo = this.convertValues(source["redo"], JournalEntry);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -51,10 +495,18 @@ export namespace utils {
 		}
 	}
 	export class RunHistoryEntry {
+	    id?: string;
 	    shortcutName: string;
 	    command: string;
 	    directory: string;
 	    timestamp: string;
+	    mode?: string;
+	    source?: string;
+	    terminal?: string;
+	    exitCode?: number;
+	    error?: string;
+	    durationMs?: number;
+	    outputTail?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new RunHistoryEntry(source);
@@ -62,12 +514,94 @@ export namespace utils {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
 	        this.shortcutName = source["shortcutName"];
 	        this.command = source["command"];
 	        this.directory = source["directory"];
 	        this.timestamp = source["timestamp"];
+	        this.mode = source["mode"];
+	        this.source = source["source"];
+	        this.terminal = source["terminal"];
+	        this.exitCode = source["exitCode"];
+	        this.error = source["error"];
+	        this.durationMs = source["durationMs"];
+	        this.outputTail = source["outputTail"];
+	    }
+	}
+	export class RunningProcess {
+	    id: string;
+	    pid: number;
+	    shortcutName: string;
+	    directory: string;
+	    mode: string;
+	    startedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RunningProcess(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.pid = source["pid"];
+	        this.shortcutName = source["shortcutName"];
+	        this.directory = source["directory"];
+	        this.mode = source["mode"];
+	        this.startedAt = source["startedAt"];
 	    }
 	}
+	
+	export class ShellExportResult {
+	    path: string;
+	    skipped?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ShellExportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	
+	
+	
+	export class TrashEntry {
+	    name: string;
+	    shortcut: ShortcutData;
+	    deletedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.shortcut = this.convertValues(source["shortcut"], ShortcutData);
+	        this.deletedAt = source["deletedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -0,0 +1,6 @@
+//go:build !bindings
+
+package main
+
+// singleInstance makes a second launch defer to the running app.
+const singleInstance = true
@@ -0,0 +1,7 @@
+//go:build bindings
+
+package main
+
+// singleInstance is off while Wails generates bindings, which runs the
+// binary even when the app is already open.
+const singleInstance = false
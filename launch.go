@@ -0,0 +1,55 @@
+package main
+
+import (
+	"yagui/utils"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// queueLaunch keeps req until the frontend collects it with
+// TakeLaunchRequests, so requests made before the UI has loaded are not
+// lost.
+func (a *App) queueLaunch(req utils.LaunchRequest) {
+	a.launchMu.Lock()
+	a.launches = append(a.launches, req)
+	a.launchMu.Unlock()
+	a.emit("launch:request")
+}
+
+// onSecondInstance handles a later launch of the binary: it brings this
+// window to the front and queues any shortcut the launch asked to run.
+func (a *App) onSecondInstance(args utils.InstanceArgs) {
+	a.raiseWindow()
+	if req, ok := utils.ParseLaunchArgs(args.Args, args.WorkingDirectory); ok {
+		a.queueLaunch(req)
+	}
+}
+
+// onURLOpen handles a yagui:// link that macOS delivers to the app, rather
+// than passing it on the command line as other platforms do.
+func (a *App) onURLOpen(link string) {
+	a.raiseWindow()
+	a.queueLaunch(utils.ParseLaunchURL(link))
+}
+
+func (a *App) raiseWindow() {
+	if a.ctx == nil {
+		return
+	}
+	runtime.WindowUnminimise(a.ctx)
+	runtime.WindowShow(a.ctx)
+	// Toggling always-on-top raises the window where the OS would otherwise
+	// only flash the taskbar entry.
+	runtime.WindowSetAlwaysOnTop(a.ctx, true)
+	runtime.WindowSetAlwaysOnTop(a.ctx, false)
+}
+
+// TakeLaunchRequests returns and clears the pending requests to run a
+// shortcut, oldest first.
+func (a *App) TakeLaunchRequests() []utils.LaunchRequest {
+	a.launchMu.Lock()
+	defer a.launchMu.Unlock()
+	reqs := a.launches
+	a.launches = nil
+	return reqs
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"embed"
+	"errors"
+	"os"
+	"yagui/utils"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+	"github.com/wailsapp/wails/v2/pkg/options/mac"
 )
 
 //go:embed all:frontend/dist
@@ -14,8 +18,32 @@ var assets embed.FS
 var AppVersion = "v0.4.0"
 
 func main() {
+	// Subcommands such as `yagui list` run headless; see cli.go.
+	if code, ok := runCLI(os.Args[1:]); ok {
+		os.Exit(code)
+	}
+
+	// A second launch hands its arguments to the running instance and exits.
+	var instance *utils.Instance
+	if singleInstance {
+		var err error
+		instance, err = utils.AcquireInstance(os.Args[1:])
+		if errors.Is(err, utils.ErrAlreadyRunning) {
+			return
+		}
+		if err != nil {
+			println("Error:", err.Error())
+			os.Exit(1)
+		}
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
+	app.instance = instance
+	wd, _ := os.Getwd()
+	if req, ok := utils.ParseLaunchArgs(os.Args[1:], wd); ok {
+		app.queueLaunch(req)
+	}
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -30,6 +58,10 @@ func main() {
 		WindowStartState: options.Maximised,
 		BackgroundColour: &options.RGBA{R: 220, G: 230, B: 241, A: 255},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
+		Mac: &mac.Options{
+			OnUrlOpen: app.onURLOpen,
+		},
 		Bind: []interface{}{
 			app,
 		},
@@ -0,0 +1,631 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	goRuntime "runtime"
+	"strconv"
+	"strings"
+)
+
+// Shell profile dialects understood by the alias importer.
+const (
+	dialectPOSIX      = "posix" // bash, zsh, sh
+	dialectFish       = "fish"
+	dialectPowerShell = "powershell"
+)
+
+// aliasDialect guesses the dialect of a profile from its file name.
+func aliasDialect(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".fish":
+		return dialectFish
+	case ".ps1":
+		return dialectPowerShell
+	}
+	return dialectPOSIX
+}
+
+// isShellProfile reports whether path should be read as a shell profile
+// rather than a JSON shortcuts file.
+func isShellProfile(path string) bool {
+	return !strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// ShellProfiles returns the shell profile files that exist for this user, so
+// they can be offered for import even though dialogs hide dotfiles.
+func ShellProfiles() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	candidates := []string{
+		filepath.Join(home, ".bashrc"),
+		filepath.Join(home, ".bash_aliases"),
+		filepath.Join(home, ".bash_profile"),
+		filepath.Join(home, ".zshrc"),
+		filepath.Join(home, ".config", "fish", "config.fish"),
+	}
+	if goRuntime.GOOS == "windows" {
+		docs := filepath.Join(home, "Documents")
+		candidates = append(candidates,
+			filepath.Join(docs, "PowerShell", "Microsoft.PowerShell_profile.ps1"),
+			filepath.Join(docs, "WindowsPowerShell", "Microsoft.PowerShell_profile.ps1"),
+		)
+	} else {
+		candidates = append(candidates, filepath.Join(home, ".config", "powershell", "Microsoft.PowerShell_profile.ps1"))
+	}
+	var found []string
+	for _, p := range candidates {
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// parseShellProfile extracts aliases and simple one-command functions from a
+// shell profile. Every shortcut is tagged with the file's base name.
+// Positional parameters in functions become {arg1}, {arg2}, ... placeholders
+// and "all arguments" becomes {raw:args?}. Aliases that can't be imported
+// whole are left out and listed in skipped with the reason.
+func parseShellProfile(path string, data []byte) (out map[string]ShortcutData, skipped []string, err error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	var lines []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimRight(sc.Text(), "\r"))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var p profileParser
+	switch aliasDialect(path) {
+	case dialectFish:
+		p = fishProfile{}
+	case dialectPowerShell:
+		p = powershellProfile{}
+	default:
+		p = posixProfile{}
+	}
+	tag := filepath.Base(path)
+	out = map[string]ShortcutData{}
+	for i := 0; i < len(lines); i++ {
+		defs, consumed := p.parse(lines, i)
+		for _, d := range defs {
+			if d.skip != "" {
+				skipped = append(skipped, fmt.Sprintf("%s (line %d): %s", d.name, i+1, d.skip))
+				continue
+			}
+			d.data.Tags = []string{tag}
+			out[d.name] = d.data
+		}
+		if consumed > 1 {
+			i += consumed - 1
+		}
+	}
+	return out, skipped, nil
+}
+
+// profileDef is one definition found in a profile. skip, when set, is why
+// it is not imported.
+type profileDef struct {
+	name string
+	data ShortcutData
+	skip string
+}
+
+// skipDefs marks every definition in defs as not imported for reason.
+func skipDefs(defs []profileDef, reason string) []profileDef {
+	for i := range defs {
+		defs[i] = profileDef{name: defs[i].name, skip: reason}
+	}
+	return defs
+}
+
+// chainedDefs handles what follows an alias command on the same line, after
+// an unquoted ";", "|" or "&". Further definitions after ";" are parsed as
+// a line of their own. Anything else most likely belongs to an unquoted
+// alias body that the shell cuts short there, so rather than import a
+// shortened command the aliases are skipped.
+func chainedDefs(p profileParser, defs []profileDef, rest string) []profileDef {
+	if rest == "" {
+		return defs
+	}
+	if rest[0] == ';' {
+		next := strings.TrimSpace(rest[1:])
+		if words, _, _, ok := profileCommand(next, dialectPOSIX); ok && len(words) == 0 {
+			return defs
+		}
+		if more, _ := p.parse([]string{next}, 0); len(more) > 0 {
+			return append(defs, more...)
+		}
+	}
+	sep := rest[:1]
+	if len(rest) > 1 && rest[1] == rest[0] {
+		sep = rest[:2]
+	}
+	return skipDefs(defs, fmt.Sprintf("the line goes on after an unquoted %q; quote the whole command to import it", sep))
+}
+
+// profileParser recognises definitions starting at lines[i] and reports how
+// many lines they span.
+type profileParser interface {
+	parse(lines []string, i int) (defs []profileDef, consumed int)
+}
+
+// functionBody collects the body of a function that opened on lines[i] with
+// rest left after the opening token. It returns the single command line of a
+// simple function, how many lines the function spans, and whether it was
+// simple. closing is the line that ends a multi-line body.
+func functionBody(lines []string, i int, rest string, closing *regexp.Regexp, inlineEnd string) (string, int, bool) {
+	rest = strings.TrimSpace(rest)
+	if inlineEnd != "" && strings.HasSuffix(rest, inlineEnd) {
+		body := strings.TrimSpace(strings.TrimSuffix(rest, inlineEnd))
+		return strings.TrimSpace(strings.TrimSuffix(body, ";")), 1, body != ""
+	}
+	var body []string
+	if rest != "" {
+		body = append(body, rest)
+	}
+	for j := i + 1; j < len(lines); j++ {
+		if closing.MatchString(lines[j]) {
+			simple := len(body) == 1
+			if !simple {
+				return "", j - i + 1, false
+			}
+			return strings.TrimSpace(strings.TrimSuffix(body[0], ";")), j - i + 1, true
+		}
+		if t := strings.TrimSpace(lines[j]); t != "" && !strings.HasPrefix(t, "#") {
+			body = append(body, t)
+		}
+	}
+	return "", 1, false
+}
+
+// POSIX (bash / zsh)
+
+var (
+	posixFuncHeader = regexp.MustCompile(`^\s*(?:function\s+([A-Za-z0-9_.:+-]+)\s*(?:\(\s*\))?|([A-Za-z0-9_.:+-]+)\s*\(\s*\))\s*\{(.*)$`)
+	posixFuncEnd    = regexp.MustCompile(`^\s*\}\s*;?\s*$`)
+	// $1 ${1} ${1:-def} $@ $* and their double-quoted whole-word forms.
+	posixArgRef = regexp.MustCompile(`^\$(?:([1-9])|\{([1-9])(?::?-([^}]*))?\}|([@*]))`)
+)
+
+type posixProfile struct{}
+
+func (posixProfile) parse(lines []string, i int) ([]profileDef, int) {
+	line := strings.TrimSpace(lines[i])
+	if m := posixFuncHeader.FindStringSubmatch(line); m != nil {
+		name := m[1] + m[2]
+		body, n, ok := functionBody(lines, i, m[3], posixFuncEnd, "}")
+		if !ok {
+			return nil, n
+		}
+		return []profileDef{{name: name, data: ShortcutData{Command: convertArgRefs(body, dialectPOSIX)}}}, n
+	}
+	words, _, rest, ok := profileCommand(line, dialectPOSIX)
+	if !ok || len(words) < 2 || words[0] != "alias" {
+		return nil, 1
+	}
+	var defs []profileDef
+	for _, w := range words[1:] {
+		if w == "--" {
+			continue
+		}
+		if strings.HasPrefix(w, "-") {
+			return nil, 1 // zsh global/suffix aliases are not standalone commands
+		}
+		name, value, ok := strings.Cut(w, "=")
+		if !ok {
+			// alias up=cd .. leaves ".." out of the alias.
+			if n := len(defs); n > 0 && defs[n-1].skip == "" {
+				defs[n-1] = profileDef{name: defs[n-1].name, skip: "its command is not quoted, so only the first word belongs to the alias"}
+			}
+			continue
+		}
+		if name == "" || value == "" {
+			continue
+		}
+		defs = append(defs, profileDef{name: name, data: ShortcutData{Command: value}})
+	}
+	return chainedDefs(posixProfile{}, defs, rest), 1
+}
+
+// fish
+
+var (
+	fishFuncHeader = regexp.MustCompile(`^\s*function\s+(\S+)(.*)$`)
+	fishFuncEnd    = regexp.MustCompile(`^\s*end\s*$`)
+	fishArgRef     = regexp.MustCompile(`^\$argv(?:\[([1-9])\])?`)
+	// The expansion of "alias name ..." and "abbr [-a] name ...", as written.
+	fishAliasBody = regexp.MustCompile(`^\s*alias\s+\S+\s+(.*)$`)
+	fishAbbrBody  = regexp.MustCompile(`^\s*abbr(?:\s+(?:-a|--add|--))*\s+\S+\s+(.*)$`)
+)
+
+type fishProfile struct{}
+
+func (fishProfile) parse(lines []string, i int) ([]profileDef, int) {
+	line := strings.TrimSpace(lines[i])
+	if m := fishFuncHeader.FindStringSubmatch(line); m != nil {
+		opts, _ := shellWords(m[2], dialectFish)
+		desc := ""
+		for j := 0; j < len(opts); j++ {
+			switch {
+			case (opts[j] == "--description" || opts[j] == "-d") && j+1 < len(opts):
+				desc = opts[j+1]
+				j++
+			case strings.HasPrefix(opts[j], "--description="):
+				desc = strings.TrimPrefix(opts[j], "--description=")
+			}
+		}
+		body, n, ok := functionBody(lines, i, "", fishFuncEnd, "")
+		if !ok {
+			return nil, n
+		}
+		return []profileDef{{name: m[1], data: ShortcutData{Command: convertArgRefs(body, dialectFish), Description: desc}}}, n
+	}
+	words, stmt, rest, ok := profileCommand(line, dialectFish)
+	if !ok || len(words) < 2 {
+		return nil, 1
+	}
+	var def profileDef
+	switch words[0] {
+	case "alias":
+		// alias name 'cmd' or alias name='cmd'
+		if name, value, ok := strings.Cut(words[1], "="); ok && len(words) == 2 {
+			def = profileDef{name: name, data: ShortcutData{Command: value}}
+		} else if m := fishAliasBody.FindStringSubmatch(stmt); m != nil && len(words) >= 3 && !strings.HasPrefix(words[1], "-") {
+			def = profileDef{name: words[1], data: ShortcutData{Command: fishExpansion(words[2:], m[1])}}
+		}
+	case "abbr":
+		// abbr [-a|--add] [--] name expansion...
+		args := words[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+			if args[0] != "-a" && args[0] != "--add" && args[0] != "--" {
+				return nil, 1
+			}
+			args = args[1:]
+		}
+		if m := fishAbbrBody.FindStringSubmatch(stmt); m != nil && len(args) >= 2 {
+			def = profileDef{name: args[0], data: ShortcutData{Command: fishExpansion(args[1:], m[1])}}
+		}
+	}
+	if def.name == "" {
+		return nil, 1
+	}
+	return chainedDefs(fishProfile{}, []profileDef{def}, rest), 1
+}
+
+// fishExpansion is the command of an alias or abbreviation whose expansion
+// is given as words. A single word is taken unquoted; several are kept as
+// written in raw, the text they came from, so their quoting survives.
+func fishExpansion(words []string, raw string) string {
+	if len(words) == 1 {
+		return words[0]
+	}
+	return strings.TrimSpace(raw)
+}
+
+// PowerShell
+
+var (
+	psFuncHeader = regexp.MustCompile(`(?i)^\s*function\s+([A-Za-z0-9_.:-]+)\s*\{(.*)$`)
+	psFuncEnd    = regexp.MustCompile(`^\s*\}\s*$`)
+	psAlias      = regexp.MustCompile(`(?i)^\s*(?:set|new)-alias\s+(.*)$`)
+	psArgRef     = regexp.MustCompile(`(?i)^\$args(?:\[([0-8])\])?`)
+)
+
+type powershellProfile struct{}
+
+func (powershellProfile) parse(lines []string, i int) ([]profileDef, int) {
+	line := strings.TrimSpace(lines[i])
+	if m := psFuncHeader.FindStringSubmatch(line); m != nil {
+		body, n, ok := functionBody(lines, i, m[2], psFuncEnd, "}")
+		if !ok || strings.HasPrefix(strings.ToLower(body), "param") {
+			return nil, n
+		}
+		return []profileDef{{name: m[1], data: ShortcutData{Command: convertArgRefs(body, dialectPowerShell)}}}, n
+	}
+	m := psAlias.FindStringSubmatch(line)
+	if m == nil {
+		return nil, 1
+	}
+	words, _, rest, ok := profileCommand(m[1], dialectPowerShell)
+	if !ok {
+		return nil, 1
+	}
+	// Set-Alias [-Name] name [-Value] value, parameters in any order.
+	var name, value string
+	var positional []string
+	for j := 0; j < len(words); j++ {
+		switch strings.ToLower(words[j]) {
+		case "-name":
+			if j+1 < len(words) {
+				name = words[j+1]
+				j++
+			}
+		case "-value":
+			if j+1 < len(words) {
+				value = words[j+1]
+				j++
+			}
+		default:
+			if strings.HasPrefix(words[j], "-") {
+				if j+1 < len(words) && !strings.HasPrefix(words[j+1], "-") && strings.EqualFold(words[j], "-Scope") {
+					j++
+				}
+				continue
+			}
+			positional = append(positional, words[j])
+		}
+	}
+	for _, w := range positional {
+		switch {
+		case name == "":
+			name = w
+		case value == "":
+			value = w
+		}
+	}
+	if name == "" || value == "" {
+		return nil, 1
+	}
+	return chainedDefs(powershellProfile{}, []profileDef{{name: name, data: ShortcutData{Command: value}}}, rest), 1
+}
+
+// helpers
+
+// shellWords splits a line into words the way the shell would, honouring
+// quotes and backslashes, and stops at an unquoted comment or ";". ok is
+// false for unterminated quotes.
+func shellWords(line, dialect string) (words []string, ok bool) {
+	words, _, _, ok = splitShell(line, dialect, ";")
+	return words, ok
+}
+
+// profileCommand is shellWords for the first command on a profile line. It
+// also stops at an unquoted "|" or "&", and returns the command's text as
+// written (without any comment) and the rest of the line from the
+// separator on.
+func profileCommand(line, dialect string) (words []string, stmt, rest string, ok bool) {
+	return splitShell(line, dialect, ";|&")
+}
+
+// splitShell does the work of shellWords, stopping at any of seps.
+func splitShell(line, dialect, seps string) (words []string, stmt, rest string, ok bool) {
+	stmt = line
+	var cur strings.Builder
+	inWord := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\'':
+			end := i + 1
+			for ; end < len(line); end++ {
+				if line[end] == '\'' {
+					if dialect == dialectPowerShell && end+1 < len(line) && line[end+1] == '\'' {
+						cur.WriteByte('\'')
+						end++
+						continue
+					}
+					break
+				}
+				if dialect == dialectFish && line[end] == '\\' && end+1 < len(line) &&
+					(line[end+1] == '\'' || line[end+1] == '\\') {
+					end++
+				}
+				cur.WriteByte(line[end])
+			}
+			if end >= len(line) {
+				return nil, "", "", false
+			}
+			i, inWord = end, true
+		case c == '"':
+			end := i + 1
+			for ; end < len(line) && line[end] != '"'; end++ {
+				if line[end] == '\\' && dialect != dialectPowerShell && end+1 < len(line) &&
+					strings.IndexByte(`"\$`+"`", line[end+1]) >= 0 {
+					end++
+				}
+				cur.WriteByte(line[end])
+			}
+			if end >= len(line) {
+				return nil, "", "", false
+			}
+			i, inWord = end, true
+		case c == '\\' && dialect != dialectPowerShell && i+1 < len(line):
+			i++
+			cur.WriteByte(line[i])
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		case c == '#' && !inWord:
+			stmt, i = line[:i], len(line)
+		case strings.IndexByte(seps, c) >= 0:
+			stmt, rest, i = line[:i], line[i:], len(line)
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, stmt, rest, true
+}
+
+// convertArgRefs rewrites a function body's references to its arguments as
+// placeholders, leaving everything else, including single-quoted text, as
+// written. A reference that makes up a whole double-quoted word, or stands
+// unquoted, becomes a quoted placeholder; one embedded in a longer
+// double-quoted string is spliced in raw so the surrounding quotes still
+// apply. Once the body has placeholders, literal text that would read as one
+// is escaped.
+func convertArgRefs(body, dialect string) string {
+	var (
+		parts []string // literal text, with a placeholder after all but the last
+		b     strings.Builder
+	)
+	emit := func(placeholder string) {
+		parts = append(parts, b.String(), placeholder)
+		b.Reset()
+	}
+	inDouble := false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\'' && !inDouble:
+			end := strings.IndexByte(body[i+1:], '\'')
+			if end < 0 {
+				b.WriteString(body[i:])
+				i = len(body)
+				continue
+			}
+			b.WriteString(body[i : i+end+2])
+			i += end + 1
+			continue
+		case c == '"':
+			// A whole-word "$1" is replaced including its quotes.
+			if !inDouble {
+				if ph, n := argRef(body[i+1:], dialect); n > 0 && i+1+n < len(body) && body[i+1+n] == '"' {
+					emit(ph.placeholder(ph.all))
+					i += n + 1
+					continue
+				}
+			}
+			inDouble = !inDouble
+		case c == '\\' && dialect != dialectPowerShell && i+1 < len(body):
+			b.WriteString(body[i : i+2])
+			i++
+			continue
+		case c == '$':
+			if ph, n := argRef(body[i:], dialect); n > 0 {
+				emit(ph.placeholder(inDouble || ph.all))
+				i += n - 1
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	if parts == nil {
+		return b.String()
+	}
+	parts = append(parts, b.String())
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = escapePlaceholders(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+// escapePlaceholders doubles the braces around anything in s that
+// parseCommand would take for a placeholder.
+func escapePlaceholders(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '{' && (i == 0 || s[i-1] != '$') {
+			if end, ok := placeholderAt(s, i); ok {
+				b.WriteString("{" + s[i:i+end+2] + "}")
+				i += end + 1
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// argPlaceholder is a converted argument reference.
+type argPlaceholder struct {
+	index int // 1-based; unused when all is set
+	def   string
+	all   bool
+}
+
+func (p argPlaceholder) placeholder(raw bool) string {
+	var s string
+	if p.all {
+		s = "args?"
+	} else {
+		s = "arg" + strconv.Itoa(p.index)
+		if p.def != "" {
+			s += "=" + p.def
+		}
+	}
+	if raw {
+		s = "raw:" + s
+	}
+	return "{" + s + "}"
+}
+
+// argRef parses an argument reference at the start of s and returns its
+// length, or 0 if there is none.
+func argRef(s, dialect string) (argPlaceholder, int) {
+	switch dialect {
+	case dialectPOSIX:
+		m := posixArgRef.FindStringSubmatch(s)
+		if m == nil {
+			return argPlaceholder{}, 0
+		}
+		if m[4] != "" {
+			return argPlaceholder{all: true}, len(m[0])
+		}
+		n, _ := strconv.Atoi(m[1] + m[2])
+		// A default containing braces or expansions cannot be a placeholder default.
+		if strings.ContainsAny(m[3], "{}$`=") {
+			return argPlaceholder{}, 0
+		}
+		return argPlaceholder{index: n, def: m[3]}, len(m[0])
+	case dialectFish:
+		m := fishArgRef.FindStringSubmatch(s)
+		if m == nil {
+			return argPlaceholder{}, 0
+		}
+		if m[1] == "" {
+			return argPlaceholder{all: true}, len(m[0])
+		}
+		n, _ := strconv.Atoi(m[1])
+		return argPlaceholder{index: n}, len(m[0])
+	case dialectPowerShell:
+		m := psArgRef.FindStringSubmatch(s)
+		if m == nil {
+			return argPlaceholder{}, 0
+		}
+		if m[1] == "" {
+			return argPlaceholder{all: true}, len(m[0])
+		}
+		n, _ := strconv.Atoi(m[1])
+		return argPlaceholder{index: n + 1}, len(m[0])
+	}
+	return argPlaceholder{}, 0
+}
+
+// previewShellProfile is PreviewImport for shell profiles. The imported
+// entries carry only their command and source tag, so existing shortcuts
+// keep their own description and tags.
+func previewShellProfile(path string, data []byte) (ImportPlan, error) {
+	incoming, skipped, err := parseShellProfile(path, data)
+	if err != nil {
+		return ImportPlan{}, err
+	}
+	if len(incoming) == 0 && len(skipped) == 0 {
+		return ImportPlan{}, fmt.Errorf("no aliases or simple functions found in %s", filepath.Base(path))
+	}
+	current, err := GetShortcuts()
+	if err != nil {
+		return ImportPlan{}, err
+	}
+	plan := planImport(path, incoming, false, current)
+	plan.Skipped = skipped
+	return plan, nil
+}
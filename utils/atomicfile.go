@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	goRuntime "runtime"
+)
+
+// writeFileAtomic replaces path with data so that readers (and a crash at
+// any point) see either the old or the new content, never a partial file.
+// The data is written to a temporary file in the same directory, fsynced and
+// renamed over path. An existing file's permissions are preserved; perm is
+// used for new files.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	noteFileContent(path, data)
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes a directory entry change (such as a rename) to disk.
+// Directories cannot be fsynced on Windows, where rename is already durable
+// enough for our purposes.
+func syncDir(dir string) {
+	if goRuntime.GOOS == "windows" {
+		return
+	}
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+}
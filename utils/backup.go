@@ -0,0 +1,339 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxBackups is how many snapshots are kept; older ones are pruned.
+const maxBackups = 20
+
+// backupFiles are the stores copied into every snapshot.
+var backupFiles = []string{"shortcuts.json", "shortcuts-meta.json"}
+
+// BackupInfo describes one snapshot in the backups folder.
+type BackupInfo struct {
+	ID            string `json:"id"`
+	CreatedAt     string `json:"createdAt"`
+	Reason        string `json:"reason"`
+	ShortcutCount int    `json:"shortcutCount"`
+}
+
+// BackupPreview is a snapshot's content compared with the current library.
+type BackupPreview struct {
+	BackupInfo
+	Shortcuts map[string]ShortcutData `json:"shortcuts"`
+	// Names that restoring would bring back, delete or overwrite.
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+func backupsDir() (string, error) {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, "backups"), nil
+}
+
+// snapshotShortcuts copies the shortcut stores into backups/<id>/ as they
+// are on disk now. It must be called under the data lock. Nothing is written
+// when there is no library yet or it is identical to the newest snapshot.
+func snapshotShortcuts(reason string) error {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return err
+	}
+	root, err := backupsDir()
+	if err != nil {
+		return err
+	}
+	files := make(map[string][]byte, len(backupFiles))
+	for _, name := range backupFiles {
+		data, err := os.ReadFile(filepath.Join(appDir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		files[name] = data
+	}
+	if files["shortcuts.json"] == nil {
+		return nil
+	}
+
+	ids, err := backupIDs()
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 && sameAsBackup(ids[len(ids)-1], files) {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	id := now.Format("20060102-150405.000")
+	dir := filepath.Join(root, id)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			break
+		}
+		dir = filepath.Join(root, fmt.Sprintf("%s-%d", id, i))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, data := range files {
+		if data == nil {
+			continue
+		}
+		if err := writeFileAtomic(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+	}
+	count := 0
+	if shortcuts, err := decodeShortcutFiles(files["shortcuts.json"], files["shortcuts-meta.json"]); err == nil {
+		count = len(shortcuts)
+	}
+	info, err := json.MarshalIndent(BackupInfo{
+		ID:            filepath.Base(dir),
+		CreatedAt:     now.Format(time.RFC3339),
+		Reason:        reason,
+		ShortcutCount: count,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "info.json"), info, 0644); err != nil {
+		return err
+	}
+	return pruneBackups()
+}
+
+// backupIDs lists snapshot IDs, oldest first.
+func backupIDs() ([]string, error) {
+	root, err := backupsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() {
+			ids = append(ids, e.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func sameAsBackup(id string, files map[string][]byte) bool {
+	root, err := backupsDir()
+	if err != nil {
+		return false
+	}
+	for name, data := range files {
+		old, err := os.ReadFile(filepath.Join(root, id, name))
+		if err != nil && !os.IsNotExist(err) {
+			return false
+		}
+		if !bytes.Equal(old, data) {
+			return false
+		}
+	}
+	return true
+}
+
+func pruneBackups() error {
+	root, err := backupsDir()
+	if err != nil {
+		return err
+	}
+	ids, err := backupIDs()
+	if err != nil {
+		return err
+	}
+	for len(ids) > maxBackups {
+		if err := os.RemoveAll(filepath.Join(root, ids[0])); err != nil {
+			return err
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// decodeShortcutFiles parses a shortcuts.json / shortcuts-meta.json pair.
+// metaData may be nil.
+func decodeShortcutFiles(cmdData, metaData []byte) (map[string]ShortcutData, error) {
+	cmdData = bytes.TrimPrefix(cmdData, []byte{0xEF, 0xBB, 0xBF})
+	cmds := map[string]string{}
+	if err := json.Unmarshal(cmdData, &cmds); err != nil {
+		// Snapshots taken before an object-format shortcuts.json was repaired.
+		var rich map[string]ShortcutData
+		if json.Unmarshal(cmdData, &rich) != nil {
+			return nil, fmt.Errorf("shortcuts.json is in an unrecognised format: %w", err)
+		}
+		return rich, nil
+	}
+	meta := map[string]shortcutMeta{}
+	if len(metaData) > 0 {
+		if err := json.Unmarshal(metaData, &meta); err != nil {
+			return nil, err
+		}
+	}
+	return mergeShortcuts(cmds, meta), nil
+}
+
+// readBackup loads a snapshot's info and shortcuts.
+func readBackup(id string) (BackupInfo, map[string]ShortcutData, error) {
+	root, err := backupsDir()
+	if err != nil {
+		return BackupInfo{}, nil, err
+	}
+	// IDs come from the frontend; never let one escape the backups folder.
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return BackupInfo{}, nil, fmt.Errorf("backup %q not found", id)
+	}
+	dir := filepath.Join(root, id)
+	cmdData, err := os.ReadFile(filepath.Join(dir, "shortcuts.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return BackupInfo{}, nil, fmt.Errorf("backup %q not found", id)
+		}
+		return BackupInfo{}, nil, err
+	}
+	metaData, err := os.ReadFile(filepath.Join(dir, "shortcuts-meta.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return BackupInfo{}, nil, err
+	}
+	shortcuts, err := decodeShortcutFiles(cmdData, metaData)
+	if err != nil {
+		return BackupInfo{}, nil, err
+	}
+	info := BackupInfo{ID: id}
+	if data, err := os.ReadFile(filepath.Join(dir, "info.json")); err == nil {
+		_ = json.Unmarshal(data, &info)
+		info.ID = id
+	}
+	info.ShortcutCount = len(shortcuts)
+	return info, shortcuts, nil
+}
+
+// ListBackups returns the available snapshots, newest first.
+func ListBackups() (backups []BackupInfo, err error) {
+	err = withDataLock(func() error {
+		ids, err := backupIDs()
+		if err != nil {
+			return err
+		}
+		backups = make([]BackupInfo, 0, len(ids))
+		for i := len(ids) - 1; i >= 0; i-- {
+			info, _, err := readBackup(ids[i])
+			if err != nil {
+				continue // skip damaged snapshots rather than hiding all of them
+			}
+			backups = append(backups, info)
+		}
+		return nil
+	})
+	return backups, err
+}
+
+// PreviewBackup returns a snapshot's shortcuts and how they differ from the
+// current library.
+func PreviewBackup(id string) (preview BackupPreview, err error) {
+	err = withDataLock(func() error {
+		info, shortcuts, err := readBackup(id)
+		if err != nil {
+			return err
+		}
+		current, err := loadShortcuts()
+		if err != nil {
+			return err
+		}
+		preview = BackupPreview{
+			BackupInfo: info,
+			Shortcuts:  shortcuts,
+			Added:      []string{},
+			Removed:    []string{},
+			Changed:    []string{},
+		}
+		for name, s := range shortcuts {
+			cur, ok := current[name]
+			switch {
+			case !ok:
+				preview.Added = append(preview.Added, name)
+			case cur.Command != s.Command || cur.Description != s.Description ||
+				strings.Join(cur.Tags, ",") != strings.Join(s.Tags, ","):
+				preview.Changed = append(preview.Changed, name)
+			}
+		}
+		for name := range current {
+			if _, ok := shortcuts[name]; !ok {
+				preview.Removed = append(preview.Removed, name)
+			}
+		}
+		sort.Strings(preview.Added)
+		sort.Strings(preview.Removed)
+		sort.Strings(preview.Changed)
+		return nil
+	})
+	return preview, err
+}
+
+// RestoreBackup replaces the library with snapshot id. The current library
+// is snapshotted first, so a restore can itself be undone.
+func RestoreBackup(id string) error {
+	return withDataLock(func() error {
+		_, shortcuts, err := readBackup(id)
+		if err != nil {
+			return err
+		}
+		if err := snapshotShortcuts("before restoring " + id); err != nil {
+			return err
+		}
+		return modifyShortcuts("Restore backup "+id, func(current map[string]ShortcutData) error {
+			for name := range current {
+				delete(current, name)
+			}
+			for name, s := range shortcuts {
+				current[name] = s
+			}
+			return nil
+		})
+	})
+}
+
+// destructiveChange describes the shortcuts that an update removes or whose
+// command it changes, or returns "" if it only adds or touches metadata.
+func destructiveChange(before, after map[string]ShortcutData) string {
+	var removed, changed []string
+	for name, b := range before {
+		s, ok := after[name]
+		switch {
+		case !ok:
+			removed = append(removed, name)
+		case s.Command != b.Command:
+			changed = append(changed, name)
+		}
+	}
+	switch {
+	case len(removed) == 0 && len(changed) == 0:
+		return ""
+	case len(removed) == 1 && len(changed) == 0:
+		return fmt.Sprintf("before removing %q", removed[0])
+	case len(removed) == 0 && len(changed) == 1:
+		return fmt.Sprintf("before changing %q", changed[0])
+	}
+	return fmt.Sprintf("before removing %d and changing %d shortcuts", len(removed), len(changed))
+}
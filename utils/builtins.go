@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BuiltinContext supplies what built-in variables are resolved against.
+//
+// Built-ins are placeholders the user never types a value for:
+//
+//	{ya.cwd}        the directory the command runs in
+//	{ya.home}       the user's home directory
+//	{ya.date}       today's date (YYYY-MM-DD)
+//	{ya.time}       the current time (HH:MM:SS)
+//	{ya.clipboard}  the current clipboard text
+//	{git.branch}    the current git branch of cwd
+//	{git.root}      the top-level directory of cwd's git repository
+//	{env.NAME}      the environment variable NAME
+//
+// The ya., git. and env. namespaces are reserved for built-ins, so that they
+// can never take over a placeholder of the user's such as {date}. An
+// unknown name in them such as {git.sha} is reported as an error rather
+// than passed to the shell; other dotted text such as {a..z} is not a
+// placeholder at all and is left as written.
+type BuiltinContext struct {
+	Dir       string
+	Clipboard func() (string, error)
+}
+
+// builtinNamespaces are the prefixes of built-in variable names.
+var builtinNamespaces = []string{"ya.", "git.", "env."}
+
+// isBuiltin reports whether a placeholder name refers to a built-in variable.
+func isBuiltin(name string) bool {
+	for _, ns := range builtinNamespaces {
+		if len(name) > len(ns) && strings.HasPrefix(name, ns) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveBuiltin returns the value of built-in variable name.
+func resolveBuiltin(name string, ctx BuiltinContext) (string, error) {
+	switch name {
+	case "ya.cwd":
+		if ctx.Dir == "" {
+			return os.Getwd()
+		}
+		return filepath.Abs(ctx.Dir)
+	case "ya.home":
+		return os.UserHomeDir()
+	case "ya.date":
+		return time.Now().Format("2006-01-02"), nil
+	case "ya.time":
+		return time.Now().Format("15:04:05"), nil
+	case "ya.clipboard":
+		if ctx.Clipboard == nil {
+			return "", errors.New("{ya.clipboard} is not available")
+		}
+		return ctx.Clipboard()
+	case "git.branch":
+		return gitOutput(ctx.Dir, "rev-parse", "--abbrev-ref", "HEAD")
+	case "git.root":
+		root, err := gitOutput(ctx.Dir, "rev-parse", "--show-toplevel")
+		return filepath.FromSlash(root), err
+	}
+	if env, ok := strings.CutPrefix(name, "env."); ok && env != "" {
+		v, set := os.LookupEnv(env)
+		if !set {
+			return "", fmt.Errorf("environment variable %s is not set", env)
+		}
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown built-in variable {%s}", name)
+}
+
+// gitOutput runs git with args in dir and returns its trimmed stdout.
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	hideWindowForCmd(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not inside a git repository", dir)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
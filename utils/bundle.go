@@ -0,0 +1,243 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// A bundle is a single JSON file holding everything needed to move Ya GUI to
+// another machine. bundleVersion is bumped whenever a field changes meaning;
+// older bundles must stay importable.
+const (
+	bundleFormat  = "yagui-bundle"
+	bundleVersion = 1
+)
+
+type bundle struct {
+	Format     string                  `json:"format"`
+	Version    int                     `json:"version"`
+	ExportedAt string                  `json:"exportedAt"`
+	Shortcuts  map[string]string       `json:"shortcuts"` // as in shortcuts.json
+	Meta       map[string]shortcutMeta `json:"meta"`      // as in shortcuts-meta.json
+	Config     *AppConfig              `json:"config,omitempty"`
+	History    []RunHistoryEntry       `json:"history,omitempty"`
+}
+
+// BundleReport says what ImportBundle restored.
+type BundleReport struct {
+	Added            int  `json:"added"`
+	Updated          int  `json:"updated"`
+	Unchanged        int  `json:"unchanged"`
+	Config           bool `json:"config"`
+	SavedDirectories int  `json:"savedDirectories"`
+	History          int  `json:"history"`
+	// RejectedTerminals counts custom terminals left out as invalid.
+	RejectedTerminals int `json:"rejectedTerminals"`
+}
+
+// parseBundle decodes data if it is a bundle. ok is false for any other
+// JSON; a bundle of an unsupported version is an error.
+func parseBundle(data []byte) (b bundle, ok bool, err error) {
+	var head struct {
+		Format  string `json:"format"`
+		Version int    `json:"version"`
+	}
+	if json.Unmarshal(data, &head) != nil || head.Format != bundleFormat {
+		return bundle{}, false, nil
+	}
+	if head.Version < 1 || head.Version > bundleVersion {
+		return bundle{}, true, fmt.Errorf("bundle version %d is not supported by this version of Ya GUI (supports up to %d)", head.Version, bundleVersion)
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return bundle{}, true, fmt.Errorf("damaged bundle: %w", err)
+	}
+	return b, true, nil
+}
+
+// ExportBundle asks for a destination and writes shortcuts, metadata and
+// config, plus run history if includeHistory is set. It returns the path
+// written, or "" if the user cancelled.
+func ExportBundle(ctx context.Context, includeHistory bool) (string, error) {
+	dest, err := runtime.SaveFileDialog(ctx, runtime.SaveDialogOptions{
+		Title:           "Export Full Backup",
+		DefaultFilename: "yagui-" + time.Now().Format("2006-01-02") + ".json",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "JSON Files", Pattern: "*.json"},
+		},
+	})
+	if err != nil || dest == "" {
+		return "", err
+	}
+	data, err := MarshalBundle(includeHistory)
+	if err != nil {
+		return "", err
+	}
+	return dest, os.WriteFile(dest, data, 0644)
+}
+
+// MarshalBundle returns the full-backup bundle as indented JSON.
+func MarshalBundle(includeHistory bool) ([]byte, error) {
+	b, err := buildBundle(includeHistory)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(b, "", "  ")
+}
+
+func buildBundle(includeHistory bool) (b bundle, err error) {
+	b = bundle{
+		Format:     bundleFormat,
+		Version:    bundleVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	err = withDataLock(func() error {
+		if b.Shortcuts, err = loadCommands(); err != nil {
+			return err
+		}
+		if b.Meta, err = loadMeta(); err != nil {
+			return err
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		b.Config = &cfg
+		if includeHistory {
+			if b.History, err = loadHistory(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return b, err
+}
+
+// ImportBundle restores a bundle written by ExportBundle. Shortcuts are
+// merged, taking the bundle's command, description, tags and pin; run counts
+// and remembered values are only taken for shortcuts that are new here.
+// Saved directories and valid custom terminals are merged by name, the
+// preferred terminal and trash retention are taken over, and history
+// entries not already present are added. Start-on-boot is left alone since
+// it has to be registered with the OS on this machine.
+func ImportBundle(path string) (report BundleReport, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+	b, ok, err := parseBundle(data)
+	if err != nil {
+		return report, err
+	}
+	if !ok {
+		return report, fmt.Errorf("%s is not a Ya GUI backup bundle", path)
+	}
+	incoming := mergeShortcuts(b.Shortcuts, b.Meta)
+
+	err = updateShortcuts("Import backup bundle", func(shortcuts map[string]ShortcutData) error {
+		for name, in := range incoming {
+			cur, exists := shortcuts[name]
+			switch {
+			case !exists:
+				shortcuts[name] = in
+				report.Added++
+			case sameContent(cur, in):
+				report.Unchanged++
+			default:
+				cur.Command, cur.Description, cur.Tags, cur.Pinned = in.Command, in.Description, in.Tags, in.Pinned
+				shortcuts[name] = cur
+				report.Updated++
+			}
+		}
+		if report.Added+report.Updated == 0 {
+			return errUnchanged
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if b.Config != nil {
+		in := *b.Config
+		err = updateConfig(func(cfg *AppConfig) error {
+			if in.PreferredTerminal != "" {
+				cfg.PreferredTerminal = in.PreferredTerminal
+			}
+			if in.TrashRetentionDays > 0 {
+				cfg.TrashRetentionDays = in.TrashRetentionDays
+			}
+			if in.DefaultDir != "" {
+				if info, err := os.Stat(in.DefaultDir); err == nil && info.IsDir() {
+					cfg.DefaultDir = in.DefaultDir
+				}
+			}
+			for _, d := range in.SavedDirectories {
+				found := false
+				for i := range cfg.SavedDirectories {
+					if cfg.SavedDirectories[i].Name == d.Name {
+						cfg.SavedDirectories[i].Path = d.Path
+						found = true
+					}
+				}
+				if !found {
+					cfg.SavedDirectories = append(cfg.SavedDirectories, d)
+				}
+				report.SavedDirectories++
+			}
+			for _, t := range in.CustomTerminals {
+				if validateTerminal(t) != nil {
+					report.RejectedTerminals++
+					continue
+				}
+				found := false
+				for i := range cfg.CustomTerminals {
+					if cfg.CustomTerminals[i].Name == t.Name {
+						cfg.CustomTerminals[i] = t
+						found = true
+					}
+				}
+				if !found {
+					cfg.CustomTerminals = append(cfg.CustomTerminals, t)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return report, err
+		}
+		report.Config = true
+	}
+
+	if len(b.History) > 0 {
+		err = updateHistory(func(entries *[]RunHistoryEntry) error {
+			seen := make(map[string]bool, len(*entries))
+			for _, e := range *entries {
+				seen[e.ID+"\x00"+e.Timestamp+"\x00"+e.ShortcutName] = true
+			}
+			for _, e := range b.History {
+				if seen[e.ID+"\x00"+e.Timestamp+"\x00"+e.ShortcutName] {
+					continue
+				}
+				*entries = append(*entries, e)
+				report.History++
+			}
+			if report.History == 0 {
+				return errUnchanged
+			}
+			sort.SliceStable(*entries, func(i, j int) bool {
+				return (*entries)[i].Timestamp < (*entries)[j].Timestamp
+			})
+			if len(*entries) > maxHistoryEntries {
+				*entries = (*entries)[len(*entries)-maxHistoryEntries:]
+			}
+			return nil
+		})
+	}
+	return report, err
+}
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	goRuntime "runtime"
+	"strings"
 )
 
 func configFilePath() (string, error) {
@@ -18,7 +19,16 @@ func configFilePath() (string, error) {
 }
 
 // GetConfig reads config.json, bootstrapping it if absent.
-func GetConfig() (AppConfig, error) {
+func GetConfig() (cfg AppConfig, err error) {
+	err = withDataLock(func() error {
+		cfg, err = loadConfig()
+		return err
+	})
+	return cfg, err
+}
+
+// loadConfig is GetConfig without the data lock.
+func loadConfig() (AppConfig, error) {
 	path, err := configFilePath()
 	if err != nil {
 		return AppConfig{}, err
@@ -62,59 +72,110 @@ func saveConfig(cfg AppConfig) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
 
 // UpdateDefaultDir persists the last-used directory.
 func UpdateDefaultDir(dir string) error {
-	cfg, err := GetConfig()
-	if err != nil {
-		return err
-	}
-	cfg.DefaultDir = dir
-	return saveConfig(cfg)
+	return updateConfig(func(cfg *AppConfig) error {
+		cfg.DefaultDir = dir
+		return nil
+	})
 }
 
 // SetPreferredTerminal saves the preferred terminal choice.
 func SetPreferredTerminal(terminal string) error {
-	cfg, err := GetConfig()
-	if err != nil {
+	return updateConfig(func(cfg *AppConfig) error {
+		cfg.PreferredTerminal = terminal
+		return nil
+	})
+}
+
+// SaveCustomTerminal adds a user-defined terminal launcher, replacing any
+// custom one of the same name.
+func SaveCustomTerminal(t TerminalLauncher) error {
+	t.Name, t.Label, t.BuiltIn = strings.TrimSpace(t.Name), strings.TrimSpace(t.Label), false
+	if err := validateTerminal(t); err != nil {
 		return err
 	}
-	cfg.PreferredTerminal = terminal
-	return saveConfig(cfg)
+	return updateConfig(func(cfg *AppConfig) error {
+		for i, c := range cfg.CustomTerminals {
+			if c.Name == t.Name {
+				cfg.CustomTerminals[i] = t
+				return nil
+			}
+		}
+		cfg.CustomTerminals = append(cfg.CustomTerminals, t)
+		return nil
+	})
+}
+
+// RemoveCustomTerminal removes a user-defined terminal launcher. If it was
+// the preferred terminal, the preference goes back to "auto".
+func RemoveCustomTerminal(name string) error {
+	return updateConfig(func(cfg *AppConfig) error {
+		filtered := cfg.CustomTerminals[:0]
+		for _, c := range cfg.CustomTerminals {
+			if c.Name != name {
+				filtered = append(filtered, c)
+			}
+		}
+		if len(filtered) == len(cfg.CustomTerminals) {
+			return fmt.Errorf("terminal %q not found", name)
+		}
+		cfg.CustomTerminals = filtered
+		if cfg.PreferredTerminal == name {
+			cfg.PreferredTerminal = "auto"
+		}
+		return nil
+	})
 }
 
 // AddSavedDirectory adds a named directory preset.
 func AddSavedDirectory(name, path string) error {
-	cfg, err := GetConfig()
-	if err != nil {
-		return err
-	}
-	for i, d := range cfg.SavedDirectories {
-		if d.Name == name {
-			cfg.SavedDirectories[i].Path = path
-			return saveConfig(cfg)
+	return updateConfig(func(cfg *AppConfig) error {
+		for i, d := range cfg.SavedDirectories {
+			if d.Name == name {
+				cfg.SavedDirectories[i].Path = path
+				return nil
+			}
 		}
-	}
-	cfg.SavedDirectories = append(cfg.SavedDirectories, SavedDir{Name: name, Path: path})
-	return saveConfig(cfg)
+		cfg.SavedDirectories = append(cfg.SavedDirectories, SavedDir{Name: name, Path: path})
+		return nil
+	})
 }
 
 // RemoveSavedDirectory removes a named directory preset.
 func RemoveSavedDirectory(name string) error {
-	cfg, err := GetConfig()
-	if err != nil {
-		return err
-	}
-	filtered := cfg.SavedDirectories[:0]
-	for _, d := range cfg.SavedDirectories {
-		if d.Name != name {
-			filtered = append(filtered, d)
+	return updateConfig(func(cfg *AppConfig) error {
+		filtered := cfg.SavedDirectories[:0]
+		for _, d := range cfg.SavedDirectories {
+			if d.Name != name {
+				filtered = append(filtered, d)
+			}
 		}
+		cfg.SavedDirectories = filtered
+		return nil
+	})
+}
+
+// SetTrashRetentionDays sets how many days deleted shortcuts are kept.
+func SetTrashRetentionDays(days int) error {
+	if days < 0 {
+		return fmt.Errorf("retention must not be negative")
 	}
-	cfg.SavedDirectories = filtered
-	return saveConfig(cfg)
+	return updateConfig(func(cfg *AppConfig) error {
+		cfg.TrashRetentionDays = days
+		return nil
+	})
+}
+
+// SetEnableAPI turns the local control API on or off.
+func SetEnableAPI(enabled bool) error {
+	return updateConfig(func(cfg *AppConfig) error {
+		cfg.EnableAPI = enabled
+		return nil
+	})
 }
 
 // SetStartOnBoot registers or unregisters autostart at login (cross-platform)
@@ -139,23 +200,18 @@ func SetStartOnBoot(enabled bool) error {
 	}
 
 	// Persist to config.json.
-	cfg, err := GetConfig()
-	if err != nil {
-		return err
-	}
-	cfg.StartOnBoot = enabled
-	return saveConfig(cfg)
+	return updateConfig(func(cfg *AppConfig) error {
+		cfg.StartOnBoot = enabled
+		return nil
+	})
 }
 
 // GetStartOnBoot reports whether autostart is currently registered.
 func GetStartOnBoot() bool {
 	switch goRuntime.GOOS {
 	case "windows":
-		cmd := exec.Command("reg", "query",
-			`HKCU\Software\Microsoft\Windows\CurrentVersion\Run`,
-			"/v", "YaGUI")
-		hideWindowForCmd(cmd)
-		return cmd.Run() == nil
+		return runReg("query", `HKCU\Software\Microsoft\Windows\CurrentVersion\Run`,
+			"/v", "YaGUI") == nil
 	case "darwin":
 		home, err := os.UserHomeDir()
 		if err != nil {
@@ -174,16 +230,18 @@ func GetStartOnBoot() bool {
 }
 
 func setStartOnBootWindows(enabled bool, exePath string) error {
-	var cmd *exec.Cmd
 	if enabled {
-		cmd = exec.Command("reg", "add",
-			`HKCU\Software\Microsoft\Windows\CurrentVersion\Run`,
+		return runReg("add", `HKCU\Software\Microsoft\Windows\CurrentVersion\Run`,
 			"/v", "YaGUI", "/t", "REG_SZ", "/d", exePath, "/f")
-	} else {
-		cmd = exec.Command("reg", "delete",
-			`HKCU\Software\Microsoft\Windows\CurrentVersion\Run`,
-			"/v", "YaGUI", "/f")
 	}
+	return runReg("delete", `HKCU\Software\Microsoft\Windows\CurrentVersion\Run`,
+		"/v", "YaGUI", "/f")
+}
+
+// runReg runs reg.exe without flashing a console window.
+func runReg(args ...string) error {
+	cmd := exec.Command("reg", args...)
+	hideWindowForCmd(cmd)
 	return cmd.Run()
 }
 
@@ -219,15 +277,18 @@ func setStartOnBootLinux(enabled bool, exePath string) error {
 	if err != nil {
 		return err
 	}
-	dir := filepath.Join(home, ".config", "autostart")
-	desktop := filepath.Join(dir, "yagui.desktop")
-
+	desktop := filepath.Join(home, ".config", "autostart", "yagui.desktop")
 	if !enabled {
 		return os.Remove(desktop)
 	}
-	content := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=YaGUI\nExec=%s\nHidden=false\nNoDisplay=false\nX-GNOME-Autostart-enabled=true\n", exePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	return writeDesktopEntry(desktop, fmt.Sprintf("Exec=%s\nHidden=false\nNoDisplay=false\nX-GNOME-Autostart-enabled=true\n", exePath))
+}
+
+// writeDesktopEntry writes a freedesktop.org .desktop file launching Ya GUI,
+// with keys added after the common ones.
+func writeDesktopEntry(path, keys string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
-	return os.WriteFile(desktop, []byte(content), 0644)
+	return os.WriteFile(path, []byte("[Desktop Entry]\nType=Application\nName=YaGUI\n"+keys), 0644)
 }
@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	goRuntime "runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The control API lets editor plugins and launchers drive the running app
+// with JSON-RPC 2.0 requests POSTed to /rpc. It listens on a Unix socket in
+// the data directory, or on a random loopback port on Windows, and every
+// request must carry "Authorization: Bearer <token>" with the token from
+// the api-token file. While it runs, api.json in the data directory tells
+// clients where to connect.
+
+const (
+	apiSocketName = "ya.sock"
+	apiTokenName  = "api-token"
+	apiInfoName   = "api.json"
+	// maxAPIRequest bounds the size of a request body.
+	maxAPIRequest = 1 << 20
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcAppError       = -32000
+)
+
+// ControlMethod handles one JSON-RPC method. params is the raw "params"
+// member, which may be empty.
+type ControlMethod func(params json.RawMessage) (interface{}, error)
+
+// ControlParamsError marks an error caused by malformed params.
+type ControlParamsError struct{ Err error }
+
+func (e ControlParamsError) Error() string { return "invalid params: " + e.Err.Error() }
+
+// DecodeParams unmarshals params into v, reporting failures as
+// ControlParamsError. Missing params leave v unchanged.
+func DecodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(strings.NewReader(string(params)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return ControlParamsError{err}
+	}
+	return nil
+}
+
+// APIInfo describes the running control server; it is also the content of
+// api.json.
+type APIInfo struct {
+	Address   string `json:"address"` // "unix:///path/ya.sock" or "http://127.0.0.1:port"
+	TokenFile string `json:"tokenFile"`
+	PID       int    `json:"pid"`
+}
+
+// ControlServer is a running control API server.
+type ControlServer struct {
+	info     APIInfo
+	server   *http.Server
+	listener net.Listener
+	infoPath string
+	socket   string
+	once     sync.Once
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// apiToken returns the control API token, creating it on first use. The
+// file is readable by the current user only.
+func apiToken() (token, path string, err error) {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return "", "", err
+	}
+	path = filepath.Join(appDir, apiTokenName)
+	data, err := os.ReadFile(path)
+	if err == nil && len(strings.TrimSpace(string(data))) >= 32 {
+		return strings.TrimSpace(string(data)), path, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", err
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(b)
+	if err := writeFileAtomic(path, []byte(token+"\n"), 0600); err != nil {
+		return "", "", err
+	}
+	return token, path, nil
+}
+
+// listenLocal listens on the Unix socket at socket, or on a loopback port
+// where Unix sockets are not used. address is "unix://<socket>" or
+// "http://127.0.0.1:<port>"; used is the socket path, or "" for loopback.
+func listenLocal(socket string) (l net.Listener, address, used string, err error) {
+	if goRuntime.GOOS != "windows" {
+		// A socket left behind by a crash would make Listen fail.
+		_ = os.Remove(socket)
+		if l, err = net.Listen("unix", socket); err == nil {
+			_ = os.Chmod(socket, 0600)
+			return l, "unix://" + socket, socket, nil
+		}
+		// Fall back to loopback, e.g. when the path is too long for a socket.
+	}
+	if l, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		return nil, "", "", err
+	}
+	return l, "http://" + l.Addr().String(), "", nil
+}
+
+// dialLocal connects to an address returned by listenLocal.
+func dialLocal(address string, timeout time.Duration) (net.Conn, error) {
+	if socket, ok := strings.CutPrefix(address, "unix://"); ok {
+		return net.DialTimeout("unix", socket, timeout)
+	}
+	return net.DialTimeout("tcp", strings.TrimPrefix(address, "http://"), timeout)
+}
+
+// StartControlServer starts the control API with methods and writes api.json.
+func StartControlServer(methods map[string]ControlMethod) (*ControlServer, error) {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return nil, err
+	}
+	token, tokenPath, err := apiToken()
+	if err != nil {
+		return nil, err
+	}
+	l, address, socket, err := listenLocal(filepath.Join(appDir, apiSocketName))
+	if err != nil {
+		return nil, err
+	}
+	s := &ControlServer{
+		info:     APIInfo{Address: address, TokenFile: tokenPath, PID: os.Getpid()},
+		listener: l,
+		infoPath: filepath.Join(appDir, apiInfoName),
+		socket:   socket,
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/rpc", rpcHandler(token, methods))
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	data, _ := json.MarshalIndent(s.info, "", "  ")
+	if err := writeFileAtomic(s.infoPath, data, 0600); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	go func() { _ = s.server.Serve(l) }()
+	return s, nil
+}
+
+// Info describes where the server listens.
+func (s *ControlServer) Info() APIInfo {
+	return s.info
+}
+
+// Close stops the server and removes api.json and the socket.
+func (s *ControlServer) Close() {
+	s.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = s.server.Shutdown(ctx)
+		_ = os.Remove(s.infoPath)
+		if s.socket != "" {
+			_ = os.Remove(s.socket)
+		}
+	})
+}
+
+// rpcHandler serves single (non-batch) JSON-RPC 2.0 requests.
+func rpcHandler(token string, methods map[string]ControlMethod) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "use POST", http.StatusMethodNotAllowed)
+			return
+		}
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+			http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxAPIRequest+1))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req rpcRequest
+		resp := rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null")}
+		switch {
+		case len(body) > maxAPIRequest:
+			resp.Error = &rpcError{rpcInvalidRequest, "request too large"}
+		case json.Unmarshal(body, &req) != nil:
+			resp.Error = &rpcError{rpcParseError, "parse error"}
+		case req.JSONRPC != "2.0" || req.Method == "":
+			resp.Error = &rpcError{rpcInvalidRequest, `expected a "jsonrpc": "2.0" request with a method`}
+		default:
+			if len(req.ID) > 0 {
+				resp.ID = req.ID
+			}
+			resp.Result, resp.Error = callMethod(methods, req)
+			// A notification (no id) gets no response body.
+			if len(req.ID) == 0 {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+func callMethod(methods map[string]ControlMethod, req rpcRequest) (result interface{}, rerr *rpcError) {
+	m, ok := methods[req.Method]
+	if !ok {
+		return nil, &rpcError{rpcMethodNotFound, fmt.Sprintf("method %q not found", req.Method)}
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			result, rerr = nil, &rpcError{rpcAppError, fmt.Sprint(p)}
+		}
+	}()
+	result, err := m(req.Params)
+	var perr ControlParamsError
+	switch {
+	case errors.As(err, &perr):
+		return nil, &rpcError{rpcInvalidParams, err.Error()}
+	case err != nil:
+		return nil, &rpcError{rpcAppError, err.Error()}
+	}
+	if result == nil {
+		result = true // "result" is required on success
+	}
+	return result, nil
+}
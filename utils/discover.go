@@ -0,0 +1,451 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProjectTask is a command found in a project directory's build files. It
+// is not saved in the library; Name is "<runner>:<task>", e.g. "make:build"
+// or "npm:test", and Command may contain placeholders for task parameters.
+type ProjectTask struct {
+	Name        string `json:"name"`
+	Command     string `json:"command"`
+	Description string `json:"description,omitempty"`
+	Runner      string `json:"runner"` // "make" | "npm" | "yarn" | "pnpm" | "bun" | "just" | "task" | "cargo" | "go"
+	Source      string `json:"source"` // file the task was found in
+}
+
+// taskDiscoverer reads the tasks a single kind of build file defines. It
+// returns nil when dir does not have that file.
+type taskDiscoverer func(dir string) ([]ProjectTask, error)
+
+var taskDiscoverers = []taskDiscoverer{
+	discoverMakeTasks,
+	discoverPackageScripts,
+	discoverJustRecipes,
+	discoverTaskfileTasks,
+	discoverCargoTasks,
+	discoverGoTasks,
+}
+
+// DiscoverProjectTasks scans dir for Makefile targets, package.json scripts,
+// justfile recipes, Taskfile tasks and Cargo / Go conventions. A build file
+// that cannot be parsed is skipped rather than failing the whole scan.
+func DiscoverProjectTasks(dir string) ([]ProjectTask, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	tasks := []ProjectTask{}
+	for _, discover := range taskDiscoverers {
+		found, err := discover(dir)
+		if err != nil {
+			continue
+		}
+		tasks = append(tasks, found...)
+	}
+	return tasks, nil
+}
+
+// FindProjectTask returns the task called name in dir.
+func FindProjectTask(dir, name string) (ProjectTask, error) {
+	tasks, err := DiscoverProjectTasks(dir)
+	if err != nil {
+		return ProjectTask{}, err
+	}
+	for _, t := range tasks {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return ProjectTask{}, fmt.Errorf("task %q not found in %s", name, dir)
+}
+
+// PromoteProjectTask saves a discovered task to the library as shortcut
+// name, tagged with its runner.
+func PromoteProjectTask(dir, taskName, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("shortcut name cannot be empty")
+	}
+	t, err := FindProjectTask(dir, taskName)
+	if err != nil {
+		return err
+	}
+	return updateShortcuts(fmt.Sprintf("Save %q", name), func(shortcuts map[string]ShortcutData) error {
+		if _, exists := shortcuts[name]; exists {
+			return fmt.Errorf("shortcut %q already exists", name)
+		}
+		shortcuts[name] = ShortcutData{
+			Command:     t.Command,
+			Description: t.Description,
+			Tags:        parseTags(t.Runner),
+		}
+		return nil
+	})
+}
+
+// readFirst reads the first of names that exists in dir.
+func readFirst(dir string, names ...string) (string, []byte, error) {
+	for _, n := range names {
+		data, err := os.ReadFile(filepath.Join(dir, n))
+		if err == nil {
+			return n, data, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", nil, err
+		}
+	}
+	return "", nil, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// fileLines splits data into lines without trailing "\r".
+func fileLines(data []byte) []string {
+	var out []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		out = append(out, strings.TrimRight(sc.Text(), "\r"))
+	}
+	return out
+}
+
+// Makefile
+
+// makeTarget matches "target:" and "a b: deps", but not ":=" assignments.
+var makeTarget = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9_./ -]*?)\s*::?(?:[^=]|$)(.*)$`)
+
+func discoverMakeTasks(dir string) ([]ProjectTask, error) {
+	source, data, err := readFirst(dir, "GNUmakefile", "makefile", "Makefile")
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var tasks []ProjectTask
+	seen := map[string]bool{}
+	var comment string
+	for _, line := range fileLines(data) {
+		if strings.HasPrefix(line, "#") {
+			comment = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		}
+		m := makeTarget.FindStringSubmatch(line)
+		if m == nil || strings.HasPrefix(line, "\t") {
+			comment = ""
+			continue
+		}
+		desc := comment
+		// "target: deps ## description" is a common self-documenting style.
+		if _, after, ok := strings.Cut(m[2], "##"); ok {
+			desc = strings.TrimSpace(after)
+		}
+		comment = ""
+		for _, target := range strings.Fields(m[1]) {
+			// Skip pattern rules, file targets and specials such as .PHONY.
+			if seen[target] || strings.ContainsAny(target, "%/.") {
+				continue
+			}
+			seen[target] = true
+			tasks = append(tasks, ProjectTask{
+				Name:        "make:" + target,
+				Command:     "make " + target,
+				Description: desc,
+				Runner:      "make",
+				Source:      source,
+			})
+		}
+	}
+	return tasks, nil
+}
+
+// package.json
+
+func discoverPackageScripts(dir string) ([]ProjectTask, error) {
+	source, data, err := readFirst(dir, "package.json")
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+	runner, run := "npm", "npm run "
+	switch {
+	case fileExists(filepath.Join(dir, "pnpm-lock.yaml")):
+		runner, run = "pnpm", "pnpm run "
+	case fileExists(filepath.Join(dir, "yarn.lock")):
+		runner, run = "yarn", "yarn run "
+	case fileExists(filepath.Join(dir, "bun.lockb")), fileExists(filepath.Join(dir, "bun.lock")):
+		runner, run = "bun", "bun run "
+	}
+	names := make([]string, 0, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tasks := make([]ProjectTask, 0, len(names))
+	for _, name := range names {
+		tasks = append(tasks, ProjectTask{
+			Name:        runner + ":" + name,
+			Command:     run + QuoteForShell(name, ShellPOSIX),
+			Description: pkg.Scripts[name],
+			Runner:      runner,
+			Source:      source,
+		})
+	}
+	return tasks, nil
+}
+
+// justfile
+
+// justRecipe matches a recipe header; "name := value" assignments, "set" and
+// "alias" lines use ":=" and are excluded by the "[^=]".
+var justRecipe = regexp.MustCompile(`^@?([A-Za-z_][A-Za-z0-9_-]*)([^:]*):(?:[^=]|$)`)
+
+func discoverJustRecipes(dir string) ([]ProjectTask, error) {
+	source, data, err := readFirst(dir, "justfile", "Justfile", ".justfile")
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var tasks []ProjectTask
+	var comment string
+	private := false
+	for _, line := range fileLines(data) {
+		switch {
+		case strings.HasPrefix(line, "#"):
+			comment = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		case strings.HasPrefix(line, "["):
+			if strings.Contains(line, "private") {
+				private = true
+			}
+			continue
+		}
+		m := justRecipe.FindStringSubmatch(line)
+		if m != nil && !private && !strings.HasPrefix(m[1], "_") {
+			if command, ok := justCommand(m[1], m[2]); ok {
+				tasks = append(tasks, ProjectTask{
+					Name:        "just:" + m[1],
+					Command:     command,
+					Description: comment,
+					Runner:      "just",
+					Source:      source,
+				})
+			}
+		}
+		comment, private = "", false
+	}
+	return tasks, nil
+}
+
+// justCommand turns a recipe's parameter list into a command whose
+// parameters are placeholders: "deploy env target='web'" becomes
+// "just deploy {env} {target=web}", and variadic "+args" / "*args" become
+// raw placeholders that may hold several words.
+func justCommand(name, params string) (string, bool) {
+	words, ok := shellWords(params, dialectPOSIX)
+	if !ok {
+		return "", false
+	}
+	parts := []string{"just", name}
+	for _, w := range words {
+		w = strings.TrimPrefix(w, "$")
+		switch {
+		case strings.HasPrefix(w, "+"):
+			parts = append(parts, "{raw:"+w[1:]+"}")
+		case strings.HasPrefix(w, "*"):
+			parts = append(parts, "{raw:"+w[1:]+"?}")
+		default:
+			param, def, hasDef := strings.Cut(w, "=")
+			switch {
+			case !hasDef:
+				parts = append(parts, "{"+param+"}")
+			case def == "" || strings.ContainsAny(def, "{}"):
+				parts = append(parts, "{"+param+"?}")
+			default:
+				parts = append(parts, "{"+param+"="+def+"}")
+			}
+		}
+	}
+	return strings.Join(parts, " "), true
+}
+
+// Taskfile
+
+var (
+	yamlKey  = regexp.MustCompile(`^(\s*)(["']?)([A-Za-z0-9_:.\-]+)(["']?):\s*(.*)$`)
+	yamlDesc = regexp.MustCompile(`^\s+(desc|summary|internal):\s*(.*)$`)
+)
+
+// discoverTaskfileTasks reads the top-level "tasks:" mapping of a Taskfile
+// with a line-based scan, which is enough for the names and descriptions.
+func discoverTaskfileTasks(dir string) ([]ProjectTask, error) {
+	source, data, err := readFirst(dir, "Taskfile.yml", "taskfile.yml", "Taskfile.yaml", "taskfile.yaml")
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var tasks []ProjectTask
+	inTasks := false
+	taskIndent := -1
+	internal := map[string]bool{}
+	var current *ProjectTask
+	for _, line := range fileLines(data) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == 0 {
+			inTasks = strings.HasPrefix(line, "tasks:")
+			current = nil
+			continue
+		}
+		if !inTasks {
+			continue
+		}
+		if taskIndent < 0 {
+			taskIndent = indent
+		}
+		if indent == taskIndent {
+			m := yamlKey.FindStringSubmatch(line)
+			if m == nil {
+				current = nil
+				continue
+			}
+			tasks = append(tasks, ProjectTask{
+				Name:    "task:" + m[3],
+				Command: "task " + QuoteForShell(m[3], ShellPOSIX),
+				Runner:  "task",
+				Source:  source,
+			})
+			current = &tasks[len(tasks)-1]
+			continue
+		}
+		if current == nil || indent <= taskIndent {
+			continue
+		}
+		if m := yamlDesc.FindStringSubmatch(line); m != nil {
+			value := strings.Trim(strings.TrimSpace(m[2]), `"'`)
+			switch m[1] {
+			case "desc", "summary":
+				// Block scalars ("|", ">") span lines the scan does not follow.
+				if (m[1] == "desc" || current.Description == "") && !strings.HasPrefix(value, "|") && !strings.HasPrefix(value, ">") {
+					current.Description = value
+				}
+			case "internal":
+				internal[current.Name] = value == "true"
+			}
+		}
+	}
+	visible := tasks[:0]
+	for _, t := range tasks {
+		if !internal[t.Name] {
+			visible = append(visible, t)
+		}
+	}
+	return visible, nil
+}
+
+// Cargo and Go
+
+func discoverCargoTasks(dir string) ([]ProjectTask, error) {
+	if !fileExists(filepath.Join(dir, "Cargo.toml")) {
+		return nil, nil
+	}
+	var tasks []ProjectTask
+	for _, c := range []struct{ name, desc string }{
+		{"build", "Compile the package"},
+		{"run", "Run the main binary"},
+		{"test", "Run the tests"},
+		{"check", "Check for errors without building"},
+		{"clippy", "Run the linter"},
+		{"fmt", "Format the code"},
+	} {
+		tasks = append(tasks, ProjectTask{
+			Name:        "cargo:" + c.name,
+			Command:     "cargo " + c.name,
+			Description: c.desc,
+			Runner:      "cargo",
+			Source:      "Cargo.toml",
+		})
+	}
+	return tasks, nil
+}
+
+func discoverGoTasks(dir string) ([]ProjectTask, error) {
+	if !fileExists(filepath.Join(dir, "go.mod")) {
+		return nil, nil
+	}
+	type goTask struct{ name, command, desc string }
+	list := []goTask{
+		{"build", "go build ./...", "Build all packages"},
+		{"test", "go test ./...", "Run all tests"},
+		{"vet", "go vet ./...", "Report suspicious constructs"},
+	}
+	if hasGoMain(dir) {
+		list = append(list, goTask{"run", "go run .", "Run the main package"})
+	}
+	if hasGoGenerate(dir) {
+		list = append(list, goTask{"generate", "go generate ./...", "Run go:generate directives"})
+	}
+	tasks := make([]ProjectTask, 0, len(list))
+	for _, t := range list {
+		tasks = append(tasks, ProjectTask{
+			Name:        "go:" + t.name,
+			Command:     t.command,
+			Description: t.desc,
+			Runner:      "go",
+			Source:      "go.mod",
+		})
+	}
+	return tasks, nil
+}
+
+// hasGoMain reports whether dir itself holds a main package.
+func hasGoMain(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		for _, line := range fileLines(data) {
+			if strings.HasPrefix(line, "package ") {
+				if strings.TrimSpace(strings.TrimPrefix(line, "package ")) == "main" {
+					return true
+				}
+				break
+			}
+		}
+	}
+	return false
+}
+
+// hasGoGenerate reports whether a Go file directly in dir has a
+// //go:generate directive.
+func hasGoGenerate(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, f := range files {
+		if data, err := os.ReadFile(f); err == nil && bytes.Contains(data, []byte("//go:generate")) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,9 +4,18 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 )
 
+// maxOutputTail is how many trailing output lines are kept per history entry.
+const maxOutputTail = 50
+
+// maxHistoryEntries caps history.json.
+const maxHistoryEntries = 500
+
 func historyFilePath() (string, error) {
 	appDir, err := getAppDataDir()
 	if err != nil {
@@ -43,12 +52,15 @@ func saveHistory(entries []RunHistoryEntry) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
 
 // GetRunHistory returns all history entries, newest first.
-func GetRunHistory() ([]RunHistoryEntry, error) {
-	entries, err := loadHistory()
+func GetRunHistory() (entries []RunHistoryEntry, err error) {
+	err = withDataLock(func() error {
+		entries, err = loadHistory()
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -59,27 +71,105 @@ func GetRunHistory() ([]RunHistoryEntry, error) {
 	return entries, nil
 }
 
-// AddRunHistoryEntry records a shortcut execution.
-func AddRunHistoryEntry(shortcutName, command, directory string) error {
-	entries, err := loadHistory()
-	if err != nil {
-		return err
+// AddRunHistoryEntry records a shortcut execution. Timestamp defaults to now.
+func AddRunHistoryEntry(entry RunHistoryEntry) error {
+	if entry.Timestamp == "" {
+		entry.Timestamp = time.Now().UTC().Format(time.RFC3339)
 	}
-	entries = append(entries, RunHistoryEntry{
-		ShortcutName: shortcutName,
-		Command:      command,
-		Directory:    directory,
-		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+	return updateHistory(func(entries *[]RunHistoryEntry) error {
+		*entries = append(*entries, entry)
+		if len(*entries) > maxHistoryEntries {
+			*entries = (*entries)[len(*entries)-maxHistoryEntries:]
+		}
+		return nil
+	})
+}
+
+// FinishRunHistoryEntry stores the outcome of run id once its process exits.
+func FinishRunHistoryEntry(id string, exit RunExit, duration time.Duration, outputTail []string) error {
+	return updateHistory(func(entries *[]RunHistoryEntry) error {
+		for i := len(*entries) - 1; i >= 0; i-- {
+			e := &(*entries)[i]
+			if e.ID != id {
+				continue
+			}
+			code := exit.ExitCode
+			e.ExitCode = &code
+			e.Error = exit.Error
+			e.DurationMs = duration.Milliseconds()
+			e.OutputTail = outputTail
+			return nil
+		}
+		return errUnchanged
 	})
-	// Cap history at 500 entries.
-	const maxEntries = 500
-	if len(entries) > maxEntries {
-		entries = entries[len(entries)-maxEntries:]
-	}
-	return saveHistory(entries)
 }
 
 // ClearRunHistory removes all history entries.
 func ClearRunHistory() error {
-	return saveHistory([]RunHistoryEntry{})
+	return withDataLock(func() error {
+		return saveHistory([]RunHistoryEntry{})
+	})
+}
+
+// ansiEscape matches CSI and OSC terminal escape sequences.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// OutputTail keeps the last maxOutputTail lines of a run's output. It is safe
+// for concurrent use.
+type OutputTail struct {
+	mu      sync.Mutex
+	lines   []string
+	partial string
+}
+
+// AddLine appends one complete line.
+func (t *OutputTail) AddLine(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.add(line)
+}
+
+// Write appends raw terminal output, splitting it into lines and stripping
+// escape sequences and carriage returns.
+func (t *OutputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parts := strings.Split(t.partial+string(p), "\n")
+	t.partial = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		t.add(cleanTerminalLine(line))
+	}
+	return len(p), nil
+}
+
+// Lines returns the retained lines, including any unterminated last line.
+func (t *OutputTail) Lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := append([]string(nil), t.lines...)
+	if last := cleanTerminalLine(t.partial); last != "" {
+		out = append(out, last)
+		if len(out) > maxOutputTail {
+			out = out[len(out)-maxOutputTail:]
+		}
+	}
+	return out
+}
+
+func (t *OutputTail) add(line string) {
+	t.lines = append(t.lines, line)
+	if len(t.lines) > maxOutputTail {
+		t.lines = t.lines[len(t.lines)-maxOutputTail:]
+	}
+}
+
+// cleanTerminalLine removes escape sequences and keeps only the text after
+// the last carriage return, which is what a terminal would show.
+func cleanTerminalLine(line string) string {
+	line = ansiEscape.ReplaceAllString(line, "")
+	line = strings.TrimRight(line, "\r")
+	if i := strings.LastIndex(line, "\r"); i >= 0 {
+		line = line[i+1:]
+	}
+	return line
 }
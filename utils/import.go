@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Import item statuses, comparing an incoming shortcut with the library.
+const (
+	ImportNew       = "new"       // no shortcut of that name yet
+	ImportIdentical = "identical" // nothing would change
+	ImportChanged   = "changed"   // the command differs
+	ImportMetadata  = "metadata"  // same command, different description/tags/pin
+)
+
+// Ways to resolve an import item.
+const (
+	ImportKeep   = "keep"   // leave the library as it is
+	ImportTheirs = "theirs" // take the incoming shortcut
+	ImportRename = "rename" // add the incoming shortcut under RenameTo
+)
+
+// ImportItem is one incoming shortcut and how it is to be imported.
+type ImportItem struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Incoming ShortcutData  `json:"incoming"`
+	Current  *ShortcutData `json:"current,omitempty"`
+	// HasMeta is set when the source carries description, tags and pin, as
+	// opposed to the CLI format's bare commands.
+	HasMeta  bool   `json:"hasMeta"`
+	Action   string `json:"action"`
+	RenameTo string `json:"renameTo,omitempty"`
+}
+
+// ImportPlan is an import preview, and once each item's Action is decided,
+// the input to ApplyImport.
+type ImportPlan struct {
+	Source string       `json:"source"`
+	Items  []ImportItem `json:"items"`
+	// Skipped lists definitions in a shell profile that were left out, with
+	// the reason.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ImportResult counts what ApplyImport did.
+type ImportResult struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Renamed int `json:"renamed"`
+	Skipped int `json:"skipped"`
+}
+
+// SelectImportFile asks the user for a file to import.
+func SelectImportFile(ctx context.Context) (string, error) {
+	return runtime.OpenFileDialog(ctx, runtime.OpenDialogOptions{
+		Title: "Import Shortcuts",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "JSON Files", Pattern: "*.json"},
+			{DisplayName: "Shell Profiles", Pattern: "*.sh;*.bash;*.zsh;*.fish;*.ps1;.bashrc;.bash_aliases;.zshrc"},
+			{DisplayName: "All Files", Pattern: "*"},
+		},
+	})
+}
+
+// parseShortcutsFile reads shortcuts in the CLI format (map[string]string),
+// the old GUI object format or a backup bundle; hasMeta reports the latter two.
+func parseShortcutsFile(data []byte) (shortcuts map[string]ShortcutData, hasMeta bool, err error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	if b, ok, err := parseBundle(data); ok {
+		if err != nil {
+			return nil, false, err
+		}
+		return mergeShortcuts(b.Shortcuts, b.Meta), true, nil
+	}
+	var cmds map[string]string
+	if json.Unmarshal(data, &cmds) == nil {
+		shortcuts = make(map[string]ShortcutData, len(cmds))
+		for name, cmd := range cmds {
+			shortcuts[name] = ShortcutData{Command: cmd}
+		}
+		return shortcuts, false, nil
+	}
+	var rich map[string]ShortcutData
+	if err := json.Unmarshal(data, &rich); err != nil {
+		return nil, false, fmt.Errorf("not a shortcuts file: %w", err)
+	}
+	for name, s := range rich {
+		s.Source = "" // only set when layering project files
+		rich[name] = s
+	}
+	return rich, true, nil
+}
+
+// PreviewImport compares the shortcuts in path with the library. New and
+// metadata-only items default to "theirs", everything else to "keep".
+// Files other than .json are read as shell profiles (see parseShellProfile).
+func PreviewImport(path string) (ImportPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImportPlan{}, err
+	}
+	if isShellProfile(path) {
+		return previewShellProfile(path, data)
+	}
+	incoming, hasMeta, err := parseShortcutsFile(data)
+	if err != nil {
+		return ImportPlan{}, err
+	}
+	current, err := GetShortcuts()
+	if err != nil {
+		return ImportPlan{}, err
+	}
+	return planImport(path, incoming, hasMeta, current), nil
+}
+
+// planImport builds the preview for incoming shortcuts from source.
+func planImport(source string, incoming map[string]ShortcutData, hasMeta bool, current map[string]ShortcutData) ImportPlan {
+	plan := ImportPlan{Source: source, Items: make([]ImportItem, 0, len(incoming))}
+	for name, in := range incoming {
+		item := ImportItem{
+			Name: name,
+			// Usage stats are never imported.
+			Incoming: ShortcutData{
+				Command:     in.Command,
+				Description: in.Description,
+				Tags:        in.Tags,
+				Pinned:      in.Pinned,
+			},
+			HasMeta: hasMeta,
+		}
+		cur, exists := current[name]
+		switch {
+		case !exists:
+			item.Status, item.Action = ImportNew, ImportTheirs
+		case cur.Command != in.Command:
+			item.Status, item.Action = ImportChanged, ImportKeep
+		case hasMeta && !sameContent(cur, in):
+			item.Status, item.Action = ImportMetadata, ImportTheirs
+		default:
+			item.Status, item.Action = ImportIdentical, ImportKeep
+		}
+		if exists {
+			c := cur
+			item.Current = &c
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	sort.Slice(plan.Items, func(i, j int) bool { return plan.Items[i].Name < plan.Items[j].Name })
+	return plan
+}
+
+// ApplyImport carries out plan. Taking "theirs" over an existing shortcut
+// replaces its command, and its description, tags and pin if the source has
+// them; run count and remembered values are kept.
+func ApplyImport(plan ImportPlan) (result ImportResult, err error) {
+	op := "Import shortcuts"
+	if plan.Source != "" {
+		op = fmt.Sprintf("Import from %s", plan.Source)
+	}
+	err = updateShortcuts(op, func(shortcuts map[string]ShortcutData) error {
+		result = ImportResult{}
+		for _, item := range plan.Items {
+			switch item.Action {
+			case ImportKeep, "":
+				result.Skipped++
+			case ImportTheirs:
+				s, exists := shortcuts[item.Name]
+				s.Command = item.Incoming.Command
+				if item.HasMeta || !exists {
+					s.Description = item.Incoming.Description
+					s.Tags = item.Incoming.Tags
+					s.Pinned = item.Incoming.Pinned
+				}
+				shortcuts[item.Name] = s
+				if exists {
+					result.Updated++
+				} else {
+					result.Added++
+				}
+			case ImportRename:
+				to := strings.TrimSpace(item.RenameTo)
+				if to == "" {
+					return fmt.Errorf("no new name given for %q", item.Name)
+				}
+				if _, exists := shortcuts[to]; exists {
+					return fmt.Errorf("a shortcut named %q already exists", to)
+				}
+				shortcuts[to] = item.Incoming
+				result.Renamed++
+			default:
+				return fmt.Errorf("unknown import action %q for %q", item.Action, item.Name)
+			}
+		}
+		if result.Added+result.Updated+result.Renamed == 0 {
+			return errUnchanged
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Only one app instance runs per data directory. The first one holds an
+// exclusive lock on instance.lock for its lifetime and listens on
+// instance.sock (a loopback port on Windows); instance.json tells later
+// launches where, together with a secret they must send back. A second
+// launch forwards its arguments there and exits.
+
+const (
+	instanceLockName   = "instance.lock"
+	instanceSocketName = "instance.sock"
+	instanceInfoName   = "instance.json"
+	// instanceWait is how long a second launch keeps trying to reach the
+	// first one, which may still be starting up.
+	instanceWait = 3 * time.Second
+)
+
+// ErrAlreadyRunning is returned by AcquireInstance after the arguments have
+// been handed to the instance that is already running.
+var ErrAlreadyRunning = errors.New("another instance is already running")
+
+// InstanceArgs are the command-line arguments of a later launch.
+type InstanceArgs struct {
+	Args             []string `json:"args"`
+	WorkingDirectory string   `json:"workingDirectory"`
+	Secret           string   `json:"secret"`
+}
+
+type instanceInfo struct {
+	Address string `json:"address"`
+	Secret  string `json:"secret"`
+	PID     int    `json:"pid"`
+}
+
+// Instance is the running app's claim on the data directory.
+type Instance struct {
+	lock     *os.File
+	listener net.Listener
+	info     instanceInfo
+	infoPath string
+	socket   string
+	once     sync.Once
+}
+
+// AcquireInstance makes this process the app's single instance. If another
+// instance holds the lock, args are forwarded to it and ErrAlreadyRunning is
+// returned.
+func AcquireInstance(args []string) (*Instance, error) {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(appDir, instanceLockName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := tryLockFile(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	infoPath := filepath.Join(appDir, instanceInfoName)
+	if !ok {
+		f.Close()
+		if err := forwardArgs(infoPath, args); err != nil {
+			return nil, fmt.Errorf("another instance is running but did not respond: %w", err)
+		}
+		return nil, ErrAlreadyRunning
+	}
+
+	l, address, socket, err := listenLocal(filepath.Join(appDir, instanceSocketName))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	secret := make([]byte, 16)
+	_, _ = rand.Read(secret)
+	inst := &Instance{
+		lock:     f,
+		listener: l,
+		info:     instanceInfo{Address: address, Secret: hex.EncodeToString(secret), PID: os.Getpid()},
+		infoPath: infoPath,
+		socket:   socket,
+	}
+	data, _ := json.MarshalIndent(inst.info, "", "  ")
+	if err := writeFileAtomic(infoPath, data, 0600); err != nil {
+		inst.Close()
+		return nil, err
+	}
+	return inst, nil
+}
+
+// Serve hands the arguments of every later launch to onArgs, one at a time.
+// Launches made before Serve is called wait in the listen queue.
+func (inst *Instance) Serve(onArgs func(InstanceArgs)) {
+	go func() {
+		for {
+			conn, err := inst.listener.Accept()
+			if err != nil {
+				return // closed
+			}
+			var msg InstanceArgs
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			err = json.NewDecoder(bufio.NewReader(conn)).Decode(&msg)
+			if err == nil && subtle.ConstantTimeCompare([]byte(msg.Secret), []byte(inst.info.Secret)) == 1 {
+				_, _ = conn.Write([]byte("ok\n"))
+				msg.Secret = ""
+				onArgs(msg)
+			}
+			conn.Close()
+		}
+	}()
+}
+
+// Close releases the instance lock and removes the socket and instance.json.
+func (inst *Instance) Close() {
+	inst.once.Do(func() {
+		_ = inst.listener.Close()
+		_ = os.Remove(inst.infoPath)
+		if inst.socket != "" {
+			_ = os.Remove(inst.socket)
+		}
+		_ = inst.lock.Close()
+	})
+}
+
+// forwardArgs sends args to the running instance, retrying while it starts.
+func forwardArgs(infoPath string, args []string) error {
+	wd, _ := os.Getwd()
+	deadline := time.Now().Add(instanceWait)
+	for {
+		err := sendArgs(infoPath, InstanceArgs{Args: args, WorkingDirectory: wd})
+		if err == nil || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func sendArgs(infoPath string, msg InstanceArgs) error {
+	data, err := os.ReadFile(infoPath)
+	if err != nil {
+		return err
+	}
+	var info instanceInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return err
+	}
+	conn, err := dialLocal(info.Address, time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	msg.Secret = info.Secret
+	if err := json.NewEncoder(conn).Encode(msg); err != nil {
+		return err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(reply) != "ok" {
+		return errors.New("unexpected reply")
+	}
+	return nil
+}
+
+// LaunchRequest asks the app to start running a shortcut, as given on the
+// command line with `--run NAME [--dir DIR] [--var NAME=VALUE]...` or by a
+// yagui:// link. The user still fills in missing values and confirms the
+// directory; requests from a link must be confirmed before that.
+type LaunchRequest struct {
+	Name   string            `json:"name"`
+	Dir    string            `json:"dir,omitempty"`
+	Values map[string]string `json:"values,omitempty"`
+	URL    string            `json:"url,omitempty"`   // the link this came from
+	Error  string            `json:"error,omitempty"` // why the link cannot be used
+}
+
+// ParseLaunchArgs extracts a LaunchRequest from command-line arguments; a
+// relative --dir is resolved against wd. A yagui:// link, as passed by the
+// OS when one is opened, takes precedence over flags. Unknown arguments,
+// such as those some OS launchers add, are ignored. ok is false if there is
+// neither a link nor --run.
+func ParseLaunchArgs(args []string, wd string) (req LaunchRequest, ok bool) {
+	for _, arg := range args {
+		if strings.HasPrefix(strings.ToLower(arg), URLScheme+":") {
+			return ParseLaunchURL(arg), true
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		flag, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		if !hasValue && i+1 < len(args) && (flag == "run" || flag == "dir" || flag == "var") {
+			i++
+			value = args[i]
+		}
+		switch flag {
+		case "run":
+			req.Name = value
+		case "dir":
+			req.Dir = value
+		case "var":
+			if k, v, ok := strings.Cut(value, "="); ok && k != "" {
+				if req.Values == nil {
+					req.Values = map[string]string{}
+				}
+				req.Values[k] = v
+			}
+		}
+	}
+	if req.Dir != "" && !filepath.IsAbs(req.Dir) && wd != "" {
+		req.Dir = filepath.Join(wd, req.Dir)
+	}
+	return req, req.Name != ""
+}
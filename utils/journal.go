@@ -0,0 +1,283 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxJournal is how many undoable operations are remembered.
+const maxJournal = 100
+
+// ShortcutChange is one shortcut's state before and after an operation.
+// A nil Before means it was created, a nil After that it was deleted.
+// Trashed means that while the shortcut is absent it lives in the trash, so
+// undo and redo move it in and out of the trash as well as the library.
+type ShortcutChange struct {
+	Name    string        `json:"name"`
+	Before  *ShortcutData `json:"before,omitempty"`
+	After   *ShortcutData `json:"after,omitempty"`
+	Trashed bool          `json:"trashed,omitempty"`
+}
+
+// JournalEntry is one undoable operation on the library.
+type JournalEntry struct {
+	Time    string           `json:"time"`
+	Op      string           `json:"op"`
+	Changes []ShortcutChange `json:"changes"`
+}
+
+// RecentChanges lists what Undo and Redo would revert or reapply, newest first.
+type RecentChanges struct {
+	Undo []JournalEntry `json:"undo"`
+	Redo []JournalEntry `json:"redo"`
+}
+
+// journal is journal.json: undo and redo stacks, oldest first.
+type journal struct {
+	Undo []JournalEntry `json:"undo"`
+	Redo []JournalEntry `json:"redo"`
+}
+
+func journalFilePath() (string, error) {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, "journal.json"), nil
+}
+
+func loadJournal() (journal, error) {
+	path, err := journalFilePath()
+	if err != nil {
+		return journal{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return journal{}, nil
+		}
+		return journal{}, err
+	}
+	var j journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		// A damaged journal only costs the undo history, never the library.
+		return journal{}, nil
+	}
+	return j, nil
+}
+
+func saveJournal(j journal) error {
+	path, err := journalFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+// cloneShortcuts copies the map so it can be compared after an update.
+func cloneShortcuts(shortcuts map[string]ShortcutData) map[string]ShortcutData {
+	out := make(map[string]ShortcutData, len(shortcuts))
+	for name, s := range shortcuts {
+		out[name] = s
+	}
+	return out
+}
+
+// diffShortcuts lists the shortcuts that differ between before and after.
+func diffShortcuts(before, after map[string]ShortcutData) []ShortcutChange {
+	var changes []ShortcutChange
+	for name, b := range before {
+		a, ok := after[name]
+		switch {
+		case !ok:
+			b := b
+			changes = append(changes, ShortcutChange{Name: name, Before: &b})
+		case !reflect.DeepEqual(a, b):
+			a, b := a, b
+			changes = append(changes, ShortcutChange{Name: name, Before: &b, After: &a})
+		}
+	}
+	for name, a := range after {
+		if _, ok := before[name]; !ok {
+			a := a
+			changes = append(changes, ShortcutChange{Name: name, After: &a})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// recordChange pushes op onto the undo stack and clears the redo stack.
+// It must be called under the data lock.
+func recordChange(op string, changes []ShortcutChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	j, err := loadJournal()
+	if err != nil {
+		return err
+	}
+	j.Undo = append(j.Undo, JournalEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Op:      op,
+		Changes: changes,
+	})
+	if len(j.Undo) > maxJournal {
+		j.Undo = j.Undo[len(j.Undo)-maxJournal:]
+	}
+	j.Redo = nil
+	return saveJournal(j)
+}
+
+// sameContent compares the user-edited fields of two shortcuts, ignoring
+// usage bookkeeping that changes on every run.
+func sameContent(a, b ShortcutData) bool {
+	return a.Command == b.Command && a.Description == b.Description &&
+		strings.Join(a.Tags, "\x00") == strings.Join(b.Tags, "\x00") && a.Pinned == b.Pinned
+}
+
+// applyChanges moves each shortcut in changes from one side of the change to
+// the other: After to Before when undoing, Before to After when redoing. It
+// refuses if a shortcut no longer matches the side it is moved from, e.g.
+// because the CLI has edited it since. Run counts, last-run times and
+// remembered values are kept from the current state.
+func applyChanges(shortcuts map[string]ShortcutData, changes []ShortcutChange, undo bool) error {
+	verb := "redo"
+	if undo {
+		verb = "undo"
+	}
+	for _, c := range changes {
+		from := c.Before
+		if undo {
+			from = c.After
+		}
+		cur, ok := shortcuts[c.Name]
+		if ok != (from != nil) || (ok && !sameContent(cur, *from)) {
+			return fmt.Errorf("cannot %s: shortcut %q has changed since", verb, c.Name)
+		}
+	}
+	for _, c := range changes {
+		to := c.After
+		if undo {
+			to = c.Before
+		}
+		cur, ok := shortcuts[c.Name]
+		if to == nil {
+			delete(shortcuts, c.Name)
+			continue
+		}
+		s := *to
+		if ok {
+			s.RunCount, s.LastRun, s.VarHistory = cur.RunCount, cur.LastRun, cur.VarHistory
+		}
+		shortcuts[c.Name] = s
+	}
+	return nil
+}
+
+// Undo reverts the most recent journaled operation and returns it.
+func Undo() (JournalEntry, error) {
+	return stepJournal(true)
+}
+
+// Redo reapplies the most recently undone operation and returns it.
+func Redo() (JournalEntry, error) {
+	return stepJournal(false)
+}
+
+func stepJournal(undo bool) (entry JournalEntry, err error) {
+	err = withDataLock(func() error {
+		j, err := loadJournal()
+		if err != nil {
+			return err
+		}
+		from, to := &j.Redo, &j.Undo
+		if undo {
+			from, to = &j.Undo, &j.Redo
+		}
+		if len(*from) == 0 {
+			if undo {
+				return errors.New("nothing to undo")
+			}
+			return errors.New("nothing to redo")
+		}
+		entry = (*from)[len(*from)-1]
+
+		shortcuts, err := loadShortcuts()
+		if err != nil {
+			return err
+		}
+		before := cloneShortcuts(shortcuts)
+		if err := applyChanges(shortcuts, entry.Changes, undo); err != nil {
+			return err
+		}
+		if err := commitShortcuts(before, shortcuts); err != nil {
+			return err
+		}
+		if err := syncTrash(entry.Changes, undo); err != nil {
+			return err
+		}
+		*from = (*from)[:len(*from)-1]
+		*to = append(*to, entry)
+		return saveJournal(j)
+	})
+	return entry, err
+}
+
+// syncTrash moves the Trashed shortcuts in changes into or out of the trash
+// to match the side of the change that applyChanges just moved them to.
+func syncTrash(changes []ShortcutChange, undo bool) error {
+	for _, c := range changes {
+		if !c.Trashed {
+			continue
+		}
+		from, to := c.Before, c.After
+		if undo {
+			from, to = c.After, c.Before
+		}
+		var err error
+		switch {
+		case to == nil && from != nil:
+			err = trashShortcut(c.Name, *from)
+		case to != nil && from == nil:
+			err = untrashShortcut(c.Name, *to)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRecentChanges returns the undo and redo stacks, newest first.
+func GetRecentChanges() (changes RecentChanges, err error) {
+	err = withDataLock(func() error {
+		j, err := loadJournal()
+		if err != nil {
+			return err
+		}
+		changes.Undo = reversed(j.Undo)
+		changes.Redo = reversed(j.Redo)
+		return nil
+	})
+	return changes, err
+}
+
+func reversed(entries []JournalEntry) []JournalEntry {
+	out := make([]JournalEntry, len(entries))
+	for i, e := range entries {
+		out[len(entries)-1-i] = e
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// The data directory is guarded by an advisory lock on ya.lock. Anything
+// that reads and then rewrites shortcuts.json, shortcuts-meta.json,
+// config.json or history.json — the GUI or the ya CLI — must hold it for the
+// whole cycle so that concurrent writers never lose each other's updates.
+//
+// The lock is exclusive and not reentrant: code running under withDataLock
+// must use the unexported load/save helpers, never the public API.
+
+// lockTimeout is how long to wait for another process to release the lock.
+const lockTimeout = 10 * time.Second
+
+// errUnchanged may be returned from an update callback to skip the save.
+var errUnchanged = errors.New("unchanged")
+
+// dataMu serialises writers inside this process; the file lock alone does
+// not, since flock is per open file description rather than per goroutine.
+var dataMu sync.Mutex
+
+func lockFilePath() (string, error) {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, "ya.lock"), nil
+}
+
+// withDataLock runs fn while holding the data directory lock.
+func withDataLock(fn func() error) error {
+	dataMu.Lock()
+	defer dataMu.Unlock()
+
+	path, err := lockFilePath()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		ok, err := tryLockFile(f)
+		if err != nil {
+			return err
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			return errors.New("the data directory is locked by another process")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	defer unlockFile(f)
+	return fn()
+}
+
+// updateShortcuts loads the shortcuts, lets fn modify them in place and saves
+// the result, all under the data lock. If fn removes a shortcut or changes a
+// command, the library is snapshotted into backups/ first. A non-empty op
+// (e.g. `Remove "build"`) records the change in the undo journal; usage
+// bookkeeping such as run counts passes "" and is not undoable.
+func updateShortcuts(op string, fn func(shortcuts map[string]ShortcutData) error) error {
+	return withDataLock(func() error {
+		return modifyShortcuts(op, fn)
+	})
+}
+
+// modifyShortcuts is updateShortcuts for callers already holding the lock.
+func modifyShortcuts(op string, fn func(shortcuts map[string]ShortcutData) error) error {
+	shortcuts, err := loadShortcuts()
+	if err != nil {
+		return err
+	}
+	before := cloneShortcuts(shortcuts)
+	if err := fn(shortcuts); err != nil {
+		if err == errUnchanged {
+			return nil
+		}
+		return err
+	}
+	if err := commitShortcuts(before, shortcuts); err != nil {
+		return err
+	}
+	if op == "" {
+		return nil
+	}
+	return recordChange(op, diffShortcuts(before, shortcuts))
+}
+
+// commitShortcuts saves after, snapshotting the library first if the step
+// from before is destructive.
+func commitShortcuts(before, after map[string]ShortcutData) error {
+	if reason := destructiveChange(before, after); reason != "" {
+		if err := snapshotShortcuts(reason); err != nil {
+			return fmt.Errorf("backup failed: %w", err)
+		}
+	}
+	return saveShortcuts(after)
+}
+
+// updateConfig is the config.json counterpart of updateShortcuts.
+func updateConfig(fn func(cfg *AppConfig) error) error {
+	return withDataLock(func() error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if err := fn(&cfg); err != nil {
+			if err == errUnchanged {
+				return nil
+			}
+			return err
+		}
+		return saveConfig(cfg)
+	})
+}
+
+// updateHistory is the history.json counterpart of updateShortcuts.
+func updateHistory(fn func(entries *[]RunHistoryEntry) error) error {
+	return withDataLock(func() error {
+		entries, err := loadHistory()
+		if err != nil {
+			return err
+		}
+		if err := fn(&entries); err != nil {
+			if err == errUnchanged {
+				return nil
+			}
+			return err
+		}
+		return saveHistory(entries)
+	})
+}
@@ -0,0 +1,280 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Placeholder describes one {variable} in a shortcut command.
+//
+// Supported forms:
+//
+//	{name}                required text value
+//	{name?}               optional text value (may be left empty)
+//	{name=default}        text value with a default
+//	{name:a|b|c}          required choice from a fixed list
+//	{name:a|b|c=b}        choice with a default
+//	{raw:name}            value spliced in without shell quoting
+//
+// Built-in variables such as {ya.cwd} or {git.branch} (see BuiltinContext) use
+// the same braces but are resolved automatically and never appear in the
+// schema. "{{name}}" produces a literal "{name}" where "{name}" would be a
+// placeholder; other doubled braces, such as Go templates' "{{.Names}}", are
+// kept as written. "${...}" is left untouched so shell parameter expansion
+// keeps working, and braces whose content is not a valid placeholder (e.g.
+// awk's '{print $1}') are also kept literally. A command without any
+// placeholders or built-ins is never changed.
+type Placeholder struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"` // "text" | "choice"
+	Default  string   `json:"default,omitempty"`
+	Choices  []string `json:"choices,omitempty"`
+	Required bool     `json:"required"`
+	Raw      bool     `json:"raw,omitempty"` // at least one occurrence opts out of quoting
+}
+
+// placeholderBody matches the text between the braces of a placeholder.
+var placeholderBody = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.\-]*)(\?)?(?::([^=]*))?(?:=(.*))?$`)
+
+// commandSegment is literal text, a reference to a placeholder, or a
+// reference to a built-in variable (source holds its original "{...}" text).
+// raw marks an occurrence whose value must not be quoted.
+type commandSegment struct {
+	text    string
+	name    string
+	builtin bool
+	raw     bool
+	source  string
+}
+
+// parseCommand splits command into literal text and placeholder references
+// and returns the placeholder definitions in order of first appearance. A
+// command without references comes back as a single literal segment.
+func parseCommand(command string) ([]commandSegment, []Placeholder, error) {
+	var segments []commandSegment
+	var defs []Placeholder
+	index := map[string]int{}
+	var lit strings.Builder
+
+	flush := func() {
+		if lit.Len() > 0 {
+			segments = append(segments, commandSegment{text: lit.String()})
+			lit.Reset()
+		}
+	}
+
+	refs := false
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		if c != '{' || (i > 0 && command[i-1] == '$') {
+			lit.WriteByte(c)
+			continue
+		}
+		if i+1 < len(command) && command[i+1] == '{' {
+			// "{{name}}" escapes what would otherwise be a placeholder.
+			if end, ok := placeholderAt(command, i+1); ok {
+				lit.WriteString(command[i+1 : i+3+end])
+				i += end + 2
+				if i+1 < len(command) && command[i+1] == '}' {
+					i++
+				}
+			} else {
+				lit.WriteByte(c)
+			}
+			continue
+		}
+		end, ok := placeholderAt(command, i)
+		if !ok {
+			lit.WriteByte(c)
+			continue
+		}
+		ph, _ := parsePlaceholder(command[i+1 : i+1+end])
+		refs = true
+		if isBuiltin(ph.Name) {
+			flush()
+			segments = append(segments, commandSegment{
+				name: ph.Name, builtin: true, raw: ph.Raw, source: command[i : i+2+end],
+			})
+			i += end + 1
+			continue
+		}
+		if j, seen := index[ph.Name]; seen {
+			merged, err := mergePlaceholder(defs[j], ph)
+			if err != nil {
+				return nil, nil, err
+			}
+			defs[j] = merged
+		} else {
+			index[ph.Name] = len(defs)
+			defs = append(defs, ph)
+		}
+		flush()
+		segments = append(segments, commandSegment{name: ph.Name, raw: ph.Raw})
+		i += end + 1
+	}
+	if !refs {
+		return []commandSegment{{text: command}}, nil, nil
+	}
+	flush()
+	return segments, defs, nil
+}
+
+// placeholderAt reports whether a placeholder starts at command[i], which
+// must be '{', and returns the length of its body.
+func placeholderAt(command string, i int) (int, bool) {
+	end := strings.IndexAny(command[i+1:], "{}")
+	if end < 0 || command[i+1+end] != '}' {
+		return 0, false
+	}
+	_, ok := parsePlaceholder(command[i+1 : i+1+end])
+	return end, ok
+}
+
+// parsePlaceholder parses the text between braces. ok is false when body is
+// not a placeholder and should be kept as literal text.
+func parsePlaceholder(body string) (Placeholder, bool) {
+	body, raw := strings.CutPrefix(body, "raw:")
+	m := placeholderBody.FindStringSubmatchIndex(body)
+	if m == nil {
+		return Placeholder{}, false
+	}
+	// Dots only belong in built-in names; {a..z} is shell brace expansion.
+	if name := body[m[2]:m[3]]; strings.Contains(name, ".") && !isBuiltin(name) {
+		return Placeholder{}, false
+	}
+	group := func(n int) string {
+		if m[2*n] < 0 {
+			return ""
+		}
+		return body[m[2*n]:m[2*n+1]]
+	}
+	ph := Placeholder{Name: group(1), Type: "text", Default: group(4), Raw: raw}
+	if m[6] >= 0 { // ":choices" present
+		for _, c := range strings.Split(group(3), "|") {
+			if c = strings.TrimSpace(c); c != "" {
+				ph.Choices = append(ph.Choices, c)
+			}
+		}
+		if len(ph.Choices) == 0 {
+			return Placeholder{}, false
+		}
+		ph.Type = "choice"
+	}
+	ph.Required = group(2) == "" && ph.Default == ""
+	return ph, true
+}
+
+// mergePlaceholder combines two occurrences of the same placeholder. A bare
+// {name} inherits the attributes of a fuller definition elsewhere.
+func mergePlaceholder(a, b Placeholder) (Placeholder, error) {
+	bare := func(p Placeholder) bool { return p.Type == "text" && p.Default == "" && p.Required }
+	raw := a.Raw || b.Raw
+	switch {
+	case bare(b):
+	case bare(a):
+		a = b
+	case a.Type == b.Type && a.Default == b.Default && a.Required == b.Required &&
+		strings.Join(a.Choices, "|") == strings.Join(b.Choices, "|"):
+	default:
+		return Placeholder{}, fmt.Errorf("conflicting definitions for placeholder {%s}", a.Name)
+	}
+	a.Raw = raw
+	return a, nil
+}
+
+// ParsePlaceholders returns the typed schema of every placeholder in command.
+func ParsePlaceholders(command string) ([]Placeholder, error) {
+	_, defs, err := parseCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	if defs == nil {
+		defs = []Placeholder{}
+	}
+	return defs, nil
+}
+
+// RenderOptions controls how RenderCommand fills in a command.
+type RenderOptions struct {
+	Values map[string]string
+	// Builtins resolves built-in variables. When nil they are left as
+	// written, which is useful for previews before a directory is chosen.
+	Builtins *BuiltinContext
+	// Shell is the dialect ("posix", "powershell" or "cmd") substituted
+	// values are quoted for. Empty splices values in unquoted.
+	Shell string
+}
+
+// RenderCommand substitutes opts.Values into command's placeholders, applying
+// defaults and rejecting missing required values or invalid choices, and
+// resolves built-in variables against opts.Builtins. Each substituted value
+// is quoted for opts.Shell unless its placeholder is marked raw; an optional
+// placeholder left blank is dropped rather than passed as an empty argument.
+func RenderCommand(command string, opts RenderOptions) (string, error) {
+	segments, defs, err := parseCommand(command)
+	if err != nil {
+		return "", err
+	}
+	resolved := make(map[string]string, len(defs))
+	for _, ph := range defs {
+		v, err := resolvePlaceholder(ph, opts.Values[ph.Name])
+		if err != nil {
+			return "", err
+		}
+		resolved[ph.Name] = v
+	}
+	builtins := map[string]string{}
+	var b strings.Builder
+	for _, seg := range segments {
+		var v string
+		switch {
+		case seg.name == "":
+			b.WriteString(seg.text)
+			continue
+		case seg.builtin && opts.Builtins == nil:
+			b.WriteString(seg.source)
+			continue
+		case seg.builtin:
+			var ok bool
+			if v, ok = builtins[seg.name]; !ok {
+				if v, err = resolveBuiltin(seg.name, *opts.Builtins); err != nil {
+					return "", err
+				}
+				builtins[seg.name] = v
+			}
+		default:
+			if v = resolved[seg.name]; v == "" {
+				continue
+			}
+		}
+		if opts.Shell != "" && !seg.raw {
+			v = QuoteForShell(v, opts.Shell)
+		}
+		b.WriteString(v)
+	}
+	return b.String(), nil
+}
+
+// resolvePlaceholder validates value against ph, falling back to its default.
+func resolvePlaceholder(ph Placeholder, value string) (string, error) {
+	if value == "" {
+		value = ph.Default
+	}
+	if value == "" {
+		if ph.Required {
+			return "", fmt.Errorf("missing value for {%s}", ph.Name)
+		}
+		return "", nil
+	}
+	if ph.Type == "choice" {
+		for _, c := range ph.Choices {
+			if c == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("invalid value %q for {%s}: must be one of %s",
+			value, ph.Name, strings.Join(ph.Choices, ", "))
+	}
+	return value, nil
+}
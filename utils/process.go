@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+)
+
+// stopTimeout is how long StopRun waits after a graceful terminate before
+// force-killing the process tree.
+const stopTimeout = 5 * time.Second
+
+// RunningProcess describes a launched shortcut that is still running.
+type RunningProcess struct {
+	ID           string `json:"id"`
+	PID          int    `json:"pid"`
+	ShortcutName string `json:"shortcutName"`
+	Directory    string `json:"directory"`
+	Mode         string `json:"mode"` // "terminal" | "inline" | "pty"
+	StartedAt    string `json:"startedAt"`
+}
+
+// ProcessExit is broadcast when any tracked run finishes.
+type ProcessExit struct {
+	ID           string `json:"id"`
+	ShortcutName string `json:"shortcutName"`
+	RunExit
+}
+
+type trackedProcess struct {
+	info     RunningProcess
+	done     chan struct{}
+	reserved bool // the ID is taken by a run that has not started yet
+}
+
+var processes = struct {
+	sync.Mutex
+	m map[string]*trackedProcess
+}{m: map[string]*trackedProcess{}}
+
+// trackProcess adds a started cmd to the registry under id.
+func trackProcess(id, shortcutName, dirPath, mode string, cmd *exec.Cmd) {
+	processes.Lock()
+	defer processes.Unlock()
+	// This replaces the reservation made by ResolveRunID, if any.
+	processes.m[id] = &trackedProcess{
+		info: RunningProcess{
+			ID:           id,
+			PID:          cmd.Process.Pid,
+			ShortcutName: shortcutName,
+			Directory:    dirPath,
+			Mode:         mode,
+			StartedAt:    time.Now().UTC().Format(time.RFC3339),
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// untrackProcess removes id from the registry once its process has exited.
+func untrackProcess(id string) {
+	processes.Lock()
+	defer processes.Unlock()
+	if p, ok := processes.m[id]; ok {
+		close(p.done)
+		delete(processes.m, id)
+	}
+}
+
+// waitProcess waits for a tracked cmd in the background, unregisters it and
+// reports its exit status to onExit.
+func waitProcess(id string, cmd *exec.Cmd, onExit func(RunExit)) {
+	go func() {
+		exit := exitStatus(cmd.Wait())
+		untrackProcess(id)
+		if onExit != nil {
+			onExit(exit)
+		}
+	}()
+}
+
+// ResolveRunID reserves id for a new run, or a fresh one if id is empty.
+// Callers pick the ID themselves when they need to subscribe to a run's
+// events before it starts. The reservation lasts until the run is tracked
+// or ReleaseRunID is called, so callers should defer ReleaseRunID.
+func ResolveRunID(id string) (string, error) {
+	processes.Lock()
+	defer processes.Unlock()
+	if id == "" {
+		id = NewRunID()
+	} else if _, taken := processes.m[id]; taken {
+		return "", fmt.Errorf("run %q is already running", id)
+	}
+	processes.m[id] = &trackedProcess{done: make(chan struct{}), reserved: true}
+	return id, nil
+}
+
+// ReleaseRunID drops the reservation of id if its run never started. It
+// leaves a started run alone.
+func ReleaseRunID(id string) {
+	processes.Lock()
+	defer processes.Unlock()
+	if p, ok := processes.m[id]; ok && p.reserved {
+		delete(processes.m, id)
+	}
+}
+
+// ListRunningProcesses returns every tracked process, oldest first.
+func ListRunningProcesses() []RunningProcess {
+	processes.Lock()
+	out := make([]RunningProcess, 0, len(processes.m))
+	for _, p := range processes.m {
+		if !p.reserved {
+			out = append(out, p.info)
+		}
+	}
+	processes.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt < out[j].StartedAt })
+	return out
+}
+
+// StopRun asks the process tree of run id to exit and force-kills it if it
+// is still running after stopTimeout. It returns once the terminate signal
+// has been sent.
+func StopRun(id string) error {
+	processes.Lock()
+	p, ok := processes.m[id]
+	processes.Unlock()
+	if !ok || p.reserved {
+		return fmt.Errorf("run %q is not running", id)
+	}
+	if err := terminateProcessTree(p.info.PID); err != nil {
+		return killProcessTree(p.info.PID)
+	}
+	go func() {
+		select {
+		case <-p.done:
+		case <-time.After(stopTimeout):
+			_ = killProcessTree(p.info.PID)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// projectFileName is the per-project shortcuts file, in the same format as
+// the CLI's shortcuts.json, that a repository can check in.
+const projectFileName = ".ya.json"
+
+// projectFiles returns the .ya.json files in dir and its parents, nearest
+// first.
+func projectFiles(dir string) []string {
+	if dir == "" {
+		return nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for {
+		path := filepath.Join(dir, projectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return files
+		}
+		dir = parent
+	}
+}
+
+// loadProjectFile reads one .ya.json and returns its shortcuts and the sum
+// of its contents. Descriptions, tags and pins are kept if the file has
+// them; usage stats only belong in the user's own library.
+func loadProjectFile(path string) (map[string]ShortcutData, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	shortcuts, _, err := parseShortcutsFile(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	for name, s := range shortcuts {
+		shortcuts[name] = ShortcutData{
+			Command:     s.Command,
+			Description: s.Description,
+			Tags:        s.Tags,
+			Pinned:      s.Pinned,
+			Source:      path,
+		}
+	}
+	return shortcuts, dataSum(data), nil
+}
+
+// projectFileTrusted reports whether cfg trusts the .ya.json at path with
+// contents sum.
+func projectFileTrusted(cfg AppConfig, path, sum string) bool {
+	return slices.Contains(cfg.TrustedProjectFiles, TrustedProjectFile{Path: path, Sum: sum})
+}
+
+// GetShortcutsForDir returns the global library with the .ya.json files
+// found by walking up from dir layered over it. A shortcut from a project
+// file replaces a library shortcut of the same name, but only once the user
+// has trusted that file, and a file nearer to dir wins over one further up.
+// Project shortcuts have Source set to the file they came from. A project
+// file that cannot be read is an error so a broken checkout does not
+// silently fall back to other commands. Trust lapses whenever the file
+// changes, so a pull cannot slip in other commands.
+func GetShortcutsForDir(dir string) (map[string]ShortcutData, error) {
+	shortcuts, err := GetShortcuts()
+	if err != nil {
+		return nil, err
+	}
+	library := make(map[string]bool, len(shortcuts))
+	for name := range shortcuts {
+		library[name] = true
+	}
+	cfg, _ := GetConfig()
+	files := projectFiles(dir)
+	// Apply the furthest file first so nearer ones override it.
+	for i := len(files) - 1; i >= 0; i-- {
+		project, sum, err := loadProjectFile(files[i])
+		if err != nil {
+			return nil, err
+		}
+		trusted := projectFileTrusted(cfg, files[i], sum)
+		for name, s := range project {
+			if library[name] && !trusted {
+				continue
+			}
+			shortcuts[name] = s
+		}
+	}
+	return shortcuts, nil
+}
+
+// UntrustedProjectFiles reports the .ya.json files above dir that would
+// replace library shortcuts if they were trusted, nearest first.
+func UntrustedProjectFiles(dir string) ([]ProjectShadow, error) {
+	library, err := GetShortcuts()
+	if err != nil {
+		return nil, err
+	}
+	cfg, _ := GetConfig()
+	var shadows []ProjectShadow
+	for _, path := range projectFiles(dir) {
+		project, sum, err := loadProjectFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if projectFileTrusted(cfg, path, sum) {
+			continue
+		}
+		var names []string
+		for name := range project {
+			if _, ok := library[name]; ok {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			shadows = append(shadows, ProjectShadow{File: path, Sum: sum, Names: names})
+		}
+	}
+	return shadows, nil
+}
+
+// TrustProjectFile lets the .ya.json at path replace library shortcuts
+// while its contents have the sum reported by UntrustedProjectFiles, which
+// is what the user was shown.
+func TrustProjectFile(path, sum string) error {
+	if filepath.Base(path) != projectFileName || !filepath.IsAbs(path) {
+		return fmt.Errorf("%s is not a project shortcuts file", path)
+	}
+	if sum == "" {
+		return fmt.Errorf("no contents given to trust for %s", path)
+	}
+	path = filepath.Clean(path)
+	return updateConfig(func(cfg *AppConfig) error {
+		// Trust in earlier contents of the file is replaced.
+		trusted := cfg.TrustedProjectFiles[:0]
+		for _, t := range cfg.TrustedProjectFiles {
+			if t.Path != path {
+				trusted = append(trusted, t)
+			}
+		}
+		cfg.TrustedProjectFiles = append(trusted, TrustedProjectFile{Path: path, Sum: sum})
+		return nil
+	})
+}
+
+// GetShortcutForDir returns the shortcut name as seen from dir.
+func GetShortcutForDir(name, dir string) (ShortcutData, error) {
+	shortcuts, err := GetShortcutsForDir(dir)
+	if err != nil {
+		return ShortcutData{}, err
+	}
+	s, ok := shortcuts[name]
+	if !ok {
+		return ShortcutData{}, fmt.Errorf("shortcut %q not found", name)
+	}
+	return s, nil
+}
+
+// GetShortcutFromSource is GetShortcutForDir for a shortcut picked from a
+// list, where source is the file it was listed from ("" for the library).
+// If name now resolves to a shortcut from elsewhere, as a .ya.json in dir
+// can make it, that is an error rather than a run of a command the user
+// never saw.
+func GetShortcutFromSource(name, source, dir string) (ShortcutData, error) {
+	s, err := GetShortcutForDir(name, dir)
+	if err != nil {
+		return ShortcutData{}, err
+	}
+	if s.Source != source {
+		return ShortcutData{}, fmt.Errorf("shortcut %q in %s comes from %s, not %s; pick it from the list again", name, dir, describeSource(s.Source), describeSource(source))
+	}
+	return s, nil
+}
+
+func describeSource(source string) string {
+	if source == "" {
+		return "the library"
+	}
+	return source
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	goRuntime "runtime"
+	"sync"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// SessionHandlers receives the raw output and final status of a PTY session.
+// Both callbacks are optional and are invoked from the session's goroutines,
+// OnExit only after the last OnOutput.
+type SessionHandlers struct {
+	OnOutput func(data []byte)
+	OnExit   func(exit RunExit)
+}
+
+// terminalSession is a command running under a pseudo-terminal.
+type terminalSession struct {
+	cmd *exec.Cmd
+	pty *os.File
+}
+
+var sessions = struct {
+	sync.Mutex
+	m map[string]*terminalSession
+}{m: map[string]*terminalSession{}}
+
+// StartTerminalSession runs command in dirPath under a pseudo-terminal of the
+// given size and registers it under id. Output is forwarded as raw bytes so an
+// xterm-compatible view can render prompts, colours and progress bars.
+func StartTerminalSession(id, shortcutName, command, dirPath string, cols, rows uint16, h SessionHandlers) error {
+	if goRuntime.GOOS == "windows" {
+		return errors.New("embedded terminal sessions are not supported on Windows")
+	}
+	p, err := exec.LookPath("bash")
+	if err != nil {
+		if p, err = exec.LookPath("sh"); err != nil {
+			return errors.New("no suitable shell found (bash or sh)")
+		}
+	}
+	cmd := exec.Command(p, "-c", command)
+	cmd.Dir = dirPath
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: cols, Rows: rows})
+	if err != nil {
+		return err
+	}
+	f = pollableFile(f)
+
+	sessions.Lock()
+	sessions.m[id] = &terminalSession{cmd: cmd, pty: f}
+	sessions.Unlock()
+	trackProcess(id, shortcutName, dirPath, "pty", cmd)
+
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := f.Read(buf)
+			if n > 0 && h.OnOutput != nil {
+				h.OnOutput(append([]byte(nil), buf[:n]...))
+			}
+			if err != nil {
+				// EOF, or EIO on Linux once the child has closed the tty.
+				return
+			}
+		}
+	}()
+	// A background child can keep the tty open after the command exits, so
+	// the session ends with the command rather than with its output.
+	waitProcess(id, cmd, func(exit RunExit) {
+		select {
+		case <-readDone:
+		case <-time.After(runWaitDelay):
+		}
+		_ = f.Close()
+		<-readDone
+
+		sessions.Lock()
+		delete(sessions.m, id)
+		sessions.Unlock()
+
+		if h.OnExit != nil {
+			h.OnExit(exit)
+		}
+	})
+	return nil
+}
+
+func getSession(id string) (*terminalSession, error) {
+	sessions.Lock()
+	defer sessions.Unlock()
+	s, ok := sessions.m[id]
+	if !ok {
+		return nil, fmt.Errorf("terminal session %q not found", id)
+	}
+	return s, nil
+}
+
+// WriteTerminalSession sends input (keystrokes or pasted text) to a session.
+func WriteTerminalSession(id, data string) error {
+	s, err := getSession(id)
+	if err != nil {
+		return err
+	}
+	_, err = s.pty.WriteString(data)
+	return err
+}
+
+// ResizeTerminalSession updates the pseudo-terminal window size of a session.
+func ResizeTerminalSession(id string, cols, rows uint16) error {
+	s, err := getSession(id)
+	if err != nil {
+		return err
+	}
+	return setTerminalSize(s.pty, cols, rows)
+}
+
+// CloseTerminalSession kills a session's process tree and releases its
+// pseudo-terminal. The session's OnExit handler still fires.
+func CloseTerminalSession(id string) error {
+	s, err := getSession(id)
+	if err != nil {
+		return err
+	}
+	// The session leader runs in its own session, so its pid is also the
+	// process group id.
+	return killProcessTree(s.cmd.Process.Pid)
+}
+
+// CloseAllTerminalSessions terminates every open session, e.g. on shutdown.
+func CloseAllTerminalSessions() {
+	sessions.Lock()
+	ids := make([]string, 0, len(sessions.m))
+	for id := range sessions.m {
+		ids = append(ids, id)
+	}
+	sessions.Unlock()
+	for _, id := range ids {
+		_ = CloseTerminalSession(id)
+	}
+}
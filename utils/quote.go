@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"regexp"
+	goRuntime "runtime"
+	"strings"
+)
+
+// Shell dialects that substituted values can be quoted for.
+const (
+	ShellPOSIX      = "posix"
+	ShellPowerShell = "powershell"
+	ShellCmd        = "cmd"
+)
+
+// Values matching these need no quoting in the respective shell.
+var (
+	posixSafe      = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+	powershellSafe = regexp.MustCompile(`^[A-Za-z0-9_+=:./\\-]+$`)
+	cmdSafe        = regexp.MustCompile(`^[A-Za-z0-9_@+:./\\-]+$`)
+)
+
+// QuoteForShell quotes value so that shell passes it to the command as a
+// single literal argument.
+func QuoteForShell(value, shell string) string {
+	switch shell {
+	case ShellPOSIX:
+		if posixSafe.MatchString(value) {
+			return value
+		}
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	case ShellPowerShell:
+		if powershellSafe.MatchString(value) {
+			return value
+		}
+		// PowerShell also treats typographic single quotes as quote marks.
+		r := strings.NewReplacer("'", "''", "‘", "‘‘", "’", "’’",
+			"‚", "‚‚", "‛", "‛‛")
+		return "'" + r.Replace(value) + "'"
+	case ShellCmd:
+		if cmdSafe.MatchString(value) {
+			return value
+		}
+		// %VAR% expands even inside double quotes, so each % is moved
+		// outside the quotes and escaped with ^.
+		r := strings.NewReplacer(`"`, `""`, "%", `"^%"`)
+		return `"` + r.Replace(value) + `"`
+	}
+	return value
+}
+
+// DefaultShell is the shell that in-app runs and terminal sessions use.
+func DefaultShell() string {
+	if goRuntime.GOOS == "windows" {
+		return ShellPowerShell
+	}
+	return ShellPOSIX
+}
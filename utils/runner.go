@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os/exec"
+	goRuntime "runtime"
+	"sync"
+	"time"
+)
+
+// runWaitDelay is how long output is still collected after an in-app run has
+// exited, in case a background child it started keeps stdout open.
+const runWaitDelay = 2 * time.Second
+
+// RunOutput is one line of output produced by an in-app run.
+type RunOutput struct {
+	Stream string `json:"stream"` // "stdout" | "stderr"
+	Line   string `json:"line"`
+}
+
+// RunExit reports how an in-app run finished.
+type RunExit struct {
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error,omitempty"`
+}
+
+// RunHandlers receives the output and final status of an in-app run.
+// Both callbacks are optional and may be invoked from other goroutines.
+type RunHandlers struct {
+	OnOutput func(out RunOutput)
+	OnExit   func(exit RunExit)
+}
+
+// NewRunID returns a random identifier for a run.
+func NewRunID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// shellCommand builds the non-interactive shell invocation used to run
+// command as a child process owned by the app.
+func shellCommand(command string) (*exec.Cmd, error) {
+	if goRuntime.GOOS == "windows" {
+		p, err := exec.LookPath("powershell")
+		if err != nil {
+			return nil, errors.New("powershell not found")
+		}
+		cmd := exec.Command(p, "-NoProfile", "-NonInteractive", "-Command", command)
+		hideWindowForCmd(cmd)
+		return cmd, nil
+	}
+	for _, sh := range []string{"bash", "sh"} {
+		if p, err := exec.LookPath(sh); err == nil {
+			return exec.Command(p, "-c", command), nil
+		}
+	}
+	return nil, errors.New("no suitable shell found (bash or sh)")
+}
+
+// StartRun executes command in dirPath without opening a terminal window and
+// tracks it in the process registry under id. stdout and stderr are delivered
+// line by line to h.OnOutput and the exit code to h.OnExit once the process
+// has finished. StartRun returns as soon as the process has been started.
+func StartRun(id, shortcutName, command, dirPath string, h RunHandlers) error {
+	cmd, err := shellCommand(command)
+	if err != nil {
+		return err
+	}
+	cmd.Dir = dirPath
+	setProcessGroup(cmd)
+
+	// Writers rather than StdoutPipe, so that Wait returns once the process
+	// has exited even if a background child still holds the output open.
+	stdout, stdoutW := io.Pipe()
+	stderr, stderrW := io.Pipe()
+	cmd.Stdout, cmd.Stderr = stdoutW, stderrW
+	cmd.WaitDelay = runWaitDelay
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	trackProcess(id, shortcutName, dirPath, "inline", cmd)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go streamLines(&wg, stdout, "stdout", h.OnOutput)
+	go streamLines(&wg, stderr, "stderr", h.OnOutput)
+
+	waitProcess(id, cmd, func(exit RunExit) {
+		// Deliver the last lines before the exit status.
+		_ = stdoutW.Close()
+		_ = stderrW.Close()
+		wg.Wait()
+		if h.OnExit != nil {
+			h.OnExit(exit)
+		}
+	})
+	return nil
+}
+
+func streamLines(wg *sync.WaitGroup, r io.Reader, stream string, onOutput func(RunOutput)) {
+	defer wg.Done()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if onOutput != nil {
+			onOutput(RunOutput{Stream: stream, Line: sc.Text()})
+		}
+	}
+	// Drain anything left (e.g. an over-long line) so the child never blocks.
+	_, _ = io.Copy(io.Discard, r)
+}
+
+// exitStatus converts the result of cmd.Wait into a RunExit. ErrWaitDelay
+// only means output was cut off after a successful exit.
+func exitStatus(err error) RunExit {
+	if err == nil || errors.Is(err, exec.ErrWaitDelay) {
+		return RunExit{ExitCode: 0}
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return RunExit{ExitCode: exitErr.ExitCode()}
+	}
+	return RunExit{ExitCode: -1, Error: err.Error()}
+}
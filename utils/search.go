@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// NamedShortcut is a shortcut together with its name, for list results.
+type NamedShortcut struct {
+	Name string `json:"name"`
+	ShortcutData
+}
+
+// SearchShortcuts returns the shortcuts whose name, command, description or
+// tags contain query (case-insensitively) and, if tag is set, that carry
+// tag. Results are ordered like the app's list: pinned first, then by name.
+func SearchShortcuts(shortcuts map[string]ShortcutData, query, tag string) []NamedShortcut {
+	q := strings.ToLower(query)
+	out := []NamedShortcut{}
+	for name, s := range shortcuts {
+		if tag != "" && !containsFold(s.Tags, tag) {
+			continue
+		}
+		if q != "" && !strings.Contains(strings.ToLower(name), q) &&
+			!strings.Contains(strings.ToLower(s.Command), q) &&
+			!strings.Contains(strings.ToLower(s.Description), q) &&
+			!containsSubstringFold(s.Tags, q) {
+			continue
+		}
+		out = append(out, NamedShortcut{Name: name, ShortcutData: s})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Pinned != out[j].Pinned {
+			return out[i].Pinned
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSubstringFold(list []string, lowerSub string) bool {
+	for _, v := range list {
+		if strings.Contains(strings.ToLower(v), lowerSub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,657 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Shell export formats.
+const (
+	ExportBash       = "bash"       // aliases and functions for bash and zsh
+	ExportFish       = "fish"       // fish functions
+	ExportPowerShell = "powershell" // PowerShell profile snippet
+	ExportScripts    = "scripts"    // one executable bash script per shortcut
+)
+
+var (
+	exportNameInvalid = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+	identInvalid      = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	envName           = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// exportShortcut is a shortcut prepared for export: its command split into
+// segments and a command name that is valid in every target shell.
+type exportShortcut struct {
+	name     string // library name
+	fn       string // function / alias / script name
+	data     ShortcutData
+	segments []commandSegment
+	params   []Placeholder // in positional order
+	err      error         // why it cannot be exported, if set
+}
+
+// prepareExport orders the shortcuts by name and assigns unique command names.
+func prepareExport(shortcuts map[string]ShortcutData) []exportShortcut {
+	names := make([]string, 0, len(shortcuts))
+	for name := range shortcuts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	used := map[string]bool{}
+	out := make([]exportShortcut, 0, len(names))
+	for _, name := range names {
+		fn := strings.Trim(exportNameInvalid.ReplaceAllString(name, "-"), "-")
+		if fn == "" || strings.HasPrefix(fn, "-") {
+			fn = "shortcut"
+		}
+		base := fn
+		for i := 2; used[strings.ToLower(fn)]; i++ {
+			fn = fmt.Sprintf("%s-%d", base, i)
+		}
+		used[strings.ToLower(fn)] = true
+
+		e := exportShortcut{name: name, fn: fn, data: shortcuts[name]}
+		e.segments, e.params, e.err = parseCommand(e.data.Command)
+		out = append(out, e)
+	}
+	return out
+}
+
+// paramIndex maps placeholder names to their 1-based position.
+func (e exportShortcut) paramIndex() map[string]int {
+	idx := make(map[string]int, len(e.params))
+	for i, p := range e.params {
+		idx[p.Name] = i + 1
+	}
+	return idx
+}
+
+// usage describes the positional parameters, e.g. "<env> [tag=latest]".
+func (e exportShortcut) usage() string {
+	parts := make([]string, 0, len(e.params))
+	for _, p := range e.params {
+		s := p.Name
+		if p.Type == "choice" {
+			s += ":" + strings.Join(p.Choices, "|")
+		}
+		if p.Default != "" {
+			s += "=" + p.Default
+		}
+		if p.Required {
+			parts = append(parts, "<"+s+">")
+		} else {
+			parts = append(parts, "["+s+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// selfCall reports whether e's command starts by calling a program named
+// like the export itself in format, and returns the rest of its first
+// segment after that word.
+func (e exportShortcut) selfCall(format string) (rest string, ok bool) {
+	if len(e.segments) == 0 || e.segments[0].name != "" {
+		return "", false
+	}
+	text := strings.TrimLeft(e.segments[0].text, " \t")
+	word := text
+	if i := strings.IndexAny(text, " \t\n;|&"); i >= 0 {
+		word = text[:i]
+	} else if len(e.segments) > 1 {
+		return "", false // the word continues into a placeholder
+	}
+	if word != e.fn && !(format == ExportPowerShell && strings.EqualFold(word, e.fn)) {
+		return "", false
+	}
+	return text[len(word):], true
+}
+
+// callSegments returns e's segments for export in format. A command that
+// starts by calling a program named like the export itself would call the
+// export again forever, so that call is sent to the program instead.
+func (e exportShortcut) callSegments(format string) []commandSegment {
+	rest, ok := e.selfCall(format)
+	if !ok {
+		return e.segments
+	}
+	word := strings.TrimLeft(e.segments[0].text, " \t")
+	word = word[:len(word)-len(rest)]
+	call := "command " + word
+	if format == ExportPowerShell {
+		call = "& (Get-Command " + word + " -CommandType Application)"
+	}
+	segments := append([]commandSegment(nil), e.segments...)
+	segments[0].text = call + rest
+	return segments
+}
+
+// plainCommand is the command of a shortcut without placeholders or
+// built-ins, as exported in format.
+func (e exportShortcut) plainCommand(format string) string {
+	var b strings.Builder
+	for _, seg := range e.callSegments(format) {
+		b.WriteString(seg.text)
+	}
+	return b.String()
+}
+
+// commentLines prefixes every line of text with "# ".
+func commentLines(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// header documents a shortcut above its definition.
+func (e exportShortcut) header() string {
+	var b strings.Builder
+	b.WriteString(commentLines(e.name))
+	if e.data.Description != "" {
+		b.WriteString(commentLines(e.data.Description))
+	}
+	if len(e.params) > 0 {
+		b.WriteString("# usage: " + e.fn + " " + e.usage() + "\n")
+	}
+	return b.String()
+}
+
+//  bash / zsh
+
+// bashDefaultEscaper escapes text inside "${1:-...}".
+var bashDefaultEscaper = strings.NewReplacer(`\`, `\\`, `$`, `\$`, "`", "\\`", `"`, `\"`, `}`, `\}`)
+
+// bashParam expands positional parameter n, falling back to p's default.
+func bashParam(n int, p Placeholder) string {
+	if p.Default != "" {
+		return fmt.Sprintf("${%d:-%s}", n, bashDefaultEscaper.Replace(p.Default))
+	}
+	return fmt.Sprintf("${%d}", n)
+}
+
+// bashRestArgs passes on the arguments after the first n, as an alias would.
+func bashRestArgs(n int) string {
+	if n == 0 {
+		return `"$@"`
+	}
+	return fmt.Sprintf(`"${@:%d}"`, n+1)
+}
+
+func bashBuiltin(name string) (string, error) {
+	switch name {
+	case "ya.cwd":
+		return "${PWD}", nil
+	case "ya.home":
+		return "${HOME}", nil
+	case "ya.date":
+		return "$(date +%F)", nil
+	case "ya.time":
+		return "$(date +%T)", nil
+	case "ya.clipboard":
+		return "$( (pbpaste || wl-paste -n || xclip -o -selection clipboard) 2>/dev/null)", nil
+	case "git.branch":
+		return "$(git rev-parse --abbrev-ref HEAD)", nil
+	case "git.root":
+		return "$(git rev-parse --show-toplevel)", nil
+	}
+	if env, ok := strings.CutPrefix(name, "env."); ok && envName.MatchString(env) {
+		return "${" + env + "}", nil
+	}
+	return "", fmt.Errorf("unknown built-in variable {%s}", name)
+}
+
+// bashBody returns the shell code of a function or script body. fail is the
+// statement that aborts it ("return 1" or "exit 1").
+func bashBody(e exportShortcut, fail string) (string, error) {
+	var b strings.Builder
+	for i, p := range e.params {
+		n := i + 1
+		if p.Required {
+			fmt.Fprintf(&b, "if [ -z \"${%d}\" ]; then echo %s >&2; %s; fi\n",
+				n, QuoteForShell("usage: "+e.fn+" "+e.usage(), ShellPOSIX), fail)
+		}
+		if p.Type == "choice" {
+			quoted := make([]string, len(p.Choices))
+			for j, c := range p.Choices {
+				quoted[j] = QuoteForShell(c, ShellPOSIX)
+			}
+			pat := strings.Join(quoted, "|")
+			if !p.Required {
+				pat += `|""`
+			}
+			fmt.Fprintf(&b, "case \"%s\" in %s) ;; *) echo %s >&2; %s ;; esac\n",
+				bashParam(n, p), pat, QuoteForShell(p.Name+" must be one of: "+strings.Join(p.Choices, ", "), ShellPOSIX), fail)
+		}
+	}
+	idx := e.paramIndex()
+	for _, seg := range e.callSegments(ExportBash) {
+		var expr string
+		switch {
+		case seg.name == "":
+			b.WriteString(seg.text)
+			continue
+		case seg.builtin:
+			v, err := bashBuiltin(seg.name)
+			if err != nil {
+				return "", err
+			}
+			expr = v
+		default:
+			n := idx[seg.name]
+			p := e.params[n-1]
+			expr = bashParam(n, p)
+			// An optional value left out is dropped, not passed as "".
+			if !p.Required && p.Default == "" && !seg.raw {
+				fmt.Fprintf(&b, `${%d:+"%s"}`, n, expr)
+				continue
+			}
+		}
+		if seg.raw {
+			b.WriteString(expr)
+		} else {
+			b.WriteString(`"` + expr + `"`)
+		}
+	}
+	b.WriteString(" " + bashRestArgs(len(e.params)) + "\n")
+	return b.String(), nil
+}
+
+func renderBash(list []exportShortcut) string {
+	var b strings.Builder
+	b.WriteString("# Ya shortcuts for bash and zsh. Source this file from ~/.bashrc or ~/.zshrc.\n")
+	for _, e := range list {
+		b.WriteString("\n")
+		if e.err != nil {
+			b.WriteString(commentLines(fmt.Sprintf("skipped %s: %v", e.name, e.err)))
+			continue
+		}
+		if len(e.params) == 0 && !hasBuiltins(e) {
+			b.WriteString(e.header())
+			b.WriteString("alias " + e.fn + "=" + QuoteForShell(e.plainCommand(ExportBash), ShellPOSIX) + "\n")
+			continue
+		}
+		body, err := bashBody(e, "return 1")
+		if err != nil {
+			b.WriteString(commentLines(fmt.Sprintf("skipped %s: %v", e.name, err)))
+			continue
+		}
+		b.WriteString(e.header())
+		b.WriteString(e.fn + "() {\n" + indent(body) + "}\n")
+	}
+	return b.String()
+}
+
+func hasBuiltins(e exportShortcut) bool {
+	for _, seg := range e.segments {
+		if seg.builtin {
+			return true
+		}
+	}
+	return false
+}
+
+func indent(body string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(body, "\n") {
+		if line != "" {
+			b.WriteString("    " + line)
+		}
+	}
+	return b.String()
+}
+
+//  fish
+
+// fishQuote single-quotes s for fish, where \ and ' are escaped inside quotes.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func fishBuiltin(name string) (string, error) {
+	switch name {
+	case "ya.cwd":
+		return `"$PWD"`, nil
+	case "ya.home":
+		return `"$HOME"`, nil
+	case "ya.date":
+		return "(date +%F)", nil
+	case "ya.time":
+		return "(date +%T)", nil
+	case "ya.clipboard":
+		return "(fish_clipboard_paste)", nil
+	case "git.branch":
+		return "(git rev-parse --abbrev-ref HEAD)", nil
+	case "git.root":
+		return "(git rev-parse --show-toplevel)", nil
+	}
+	if env, ok := strings.CutPrefix(name, "env."); ok && envName.MatchString(env) {
+		return `"$` + env + `"`, nil
+	}
+	return "", fmt.Errorf("unknown built-in variable {%s}", name)
+}
+
+func fishBody(e exportShortcut) (string, error) {
+	var b strings.Builder
+	usage := fishQuote("usage: " + e.fn + " " + e.usage())
+	for i, p := range e.params {
+		v := fmt.Sprintf("arg%d", i+1)
+		fmt.Fprintf(&b, "set -l %s $argv[%d]\n", v, i+1)
+		if p.Default != "" {
+			fmt.Fprintf(&b, "test -n \"$%s\"; or set %s %s\n", v, v, fishQuote(p.Default))
+		}
+		if p.Required {
+			fmt.Fprintf(&b, "if test -z \"$%s\"; echo %s >&2; return 1; end\n", v, usage)
+		}
+		if p.Type == "choice" {
+			quoted := make([]string, len(p.Choices))
+			for j, c := range p.Choices {
+				quoted[j] = fishQuote(c)
+			}
+			fmt.Fprintf(&b, "if test -n \"$%s\"; and not contains -- \"$%s\" %s; echo %s >&2; return 1; end\n",
+				v, v, strings.Join(quoted, " "), fishQuote(p.Name+" must be one of: "+strings.Join(p.Choices, ", ")))
+		}
+	}
+	idx := e.paramIndex()
+	for _, seg := range e.callSegments(ExportFish) {
+		switch {
+		case seg.name == "":
+			b.WriteString(seg.text)
+		case seg.builtin:
+			v, err := fishBuiltin(seg.name)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+		default:
+			// fish never word-splits variables, so quoting is only needed
+			// to keep an empty value as an (empty) argument.
+			if seg.raw {
+				fmt.Fprintf(&b, "{$arg%d}", idx[seg.name])
+			} else {
+				fmt.Fprintf(&b, "\"$arg%d\"", idx[seg.name])
+			}
+		}
+	}
+	if len(e.params) == 0 {
+		b.WriteString(" $argv\n")
+	} else {
+		fmt.Fprintf(&b, " $argv[%d..-1]\n", len(e.params)+1)
+	}
+	return b.String(), nil
+}
+
+func renderFish(list []exportShortcut) string {
+	var b strings.Builder
+	b.WriteString("# Ya shortcuts for fish. Save as ~/.config/fish/conf.d/ya.fish.\n")
+	for _, e := range list {
+		b.WriteString("\n")
+		if e.err != nil {
+			b.WriteString(commentLines(fmt.Sprintf("skipped %s: %v", e.name, e.err)))
+			continue
+		}
+		var body string
+		if len(e.params) == 0 && !hasBuiltins(e) {
+			body = e.plainCommand(ExportFish) + " $argv\n"
+		} else {
+			var err error
+			if body, err = fishBody(e); err != nil {
+				b.WriteString(commentLines(fmt.Sprintf("skipped %s: %v", e.name, err)))
+				continue
+			}
+		}
+		b.WriteString(e.header())
+		b.WriteString("function " + e.fn)
+		if e.data.Description != "" {
+			b.WriteString(" --description " + fishQuote(strings.Join(strings.Fields(e.data.Description), " ")))
+		}
+		b.WriteString("\n" + indent(body) + "end\n")
+	}
+	return b.String()
+}
+
+//  PowerShell
+
+// psAutomatic are PowerShell's automatic variables (lower case), which must
+// not be used as parameter names.
+var psAutomatic = []string{"_", "args", "env", "error", "false", "home", "host", "input",
+	"matches", "myinvocation", "null", "pid", "profile", "psitem", "pwd", "this", "true"}
+
+// psQuote single-quotes s for PowerShell.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func psBuiltin(name string) (string, error) {
+	switch name {
+	case "ya.cwd":
+		return "$PWD.Path", nil
+	case "ya.home":
+		return "$HOME", nil
+	case "ya.date":
+		return "(Get-Date -Format yyyy-MM-dd)", nil
+	case "ya.time":
+		return "(Get-Date -Format HH:mm:ss)", nil
+	case "ya.clipboard":
+		return "(Get-Clipboard -Raw)", nil
+	case "git.branch":
+		return "(git rev-parse --abbrev-ref HEAD)", nil
+	case "git.root":
+		return "(git rev-parse --show-toplevel)", nil
+	}
+	if env, ok := strings.CutPrefix(name, "env."); ok && envName.MatchString(env) {
+		return "$env:" + env, nil
+	}
+	return "", fmt.Errorf("unknown built-in variable {%s}", name)
+}
+
+func psBody(e exportShortcut) (string, error) {
+	// PowerShell parameters are named as well as positional, so they keep
+	// the placeholder's name where it is a valid identifier.
+	vars := make(map[string]string, len(e.params))
+	used := map[string]bool{}
+	for _, v := range psAutomatic {
+		used[v] = true
+	}
+	var decls []string
+	for i, p := range e.params {
+		v := strings.Trim(identInvalid.ReplaceAllString(p.Name, "_"), "_")
+		if v == "" || used[strings.ToLower(v)] {
+			v = fmt.Sprintf("arg%d", i+1)
+		}
+		used[strings.ToLower(v)] = true
+		vars[p.Name] = v
+
+		var attrs []string
+		if p.Required {
+			attrs = append(attrs, "[Parameter(Mandatory)]")
+		}
+		if p.Type == "choice" {
+			quoted := make([]string, len(p.Choices))
+			for j, c := range p.Choices {
+				quoted[j] = psQuote(c)
+			}
+			attrs = append(attrs, "[ValidateSet("+strings.Join(quoted, ", ")+")]")
+		}
+		decl := strings.Join(append(attrs, "[string]$"+v), " ")
+		if p.Default != "" {
+			decl += " = " + psQuote(p.Default)
+		}
+		decls = append(decls, decl)
+	}
+
+	var b strings.Builder
+	if len(decls) > 0 {
+		b.WriteString("param(" + strings.Join(decls, ", ") + ")\n")
+	}
+	// Built-ins are evaluated into variables first: only a variable can be
+	// directly followed by more text in the same argument.
+	builtins := map[string]string{}
+	for _, seg := range e.segments {
+		if !seg.builtin || builtins[seg.name] != "" {
+			continue
+		}
+		expr, err := psBuiltin(seg.name)
+		if err != nil {
+			return "", err
+		}
+		v := "ya_" + identInvalid.ReplaceAllString(seg.name, "_")
+		builtins[seg.name] = v
+		b.WriteString("$" + v + " = " + expr + "\n")
+	}
+	for _, seg := range e.callSegments(ExportPowerShell) {
+		switch {
+		case seg.name == "":
+			b.WriteString(seg.text)
+		case seg.builtin:
+			b.WriteString("${" + builtins[seg.name] + "}")
+		default:
+			// A variable is always passed as a single argument.
+			b.WriteString("${" + vars[seg.name] + "}")
+		}
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+func renderPowerShell(list []exportShortcut) string {
+	var b strings.Builder
+	b.WriteString("# Ya shortcuts for PowerShell. Add to your profile: . path\\to\\this-file.ps1\n")
+	for _, e := range list {
+		b.WriteString("\n")
+		if e.err != nil {
+			b.WriteString(commentLines(fmt.Sprintf("skipped %s: %v", e.name, e.err)))
+			continue
+		}
+		var body string
+		if len(e.params) == 0 && !hasBuiltins(e) {
+			body = e.plainCommand(ExportPowerShell) + " @args\n"
+		} else {
+			var err error
+			if body, err = psBody(e); err != nil {
+				b.WriteString(commentLines(fmt.Sprintf("skipped %s: %v", e.name, err)))
+				continue
+			}
+		}
+		b.WriteString(e.header())
+		b.WriteString("function " + e.fn + " {\n" + indent(body) + "}\n")
+	}
+	return b.String()
+}
+
+//  scripts
+
+// scriptHead starts every exported script; a file in the export folder
+// that does not start with it is not ours to replace.
+const scriptHead = "#!/usr/bin/env bash\n# Written by Ya GUI's shell export.\n"
+
+// writeScripts writes one executable bash script per shortcut into dir and
+// returns the shortcuts it left out, each with the reason.
+func writeScripts(dir string, list []exportShortcut) (skipped []string, err error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	for _, e := range list {
+		if e.err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", e.name, e.err))
+			continue
+		}
+		// `command` would find the script itself once dir is on PATH.
+		if _, self := e.selfCall(ExportBash); self {
+			skipped = append(skipped, fmt.Sprintf("%s: the script %s would run itself", e.name, e.fn))
+			continue
+		}
+		body, err := bashBody(e, "exit 1")
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", e.name, err))
+			continue
+		}
+		path := filepath.Join(dir, e.fn)
+		if data, err := os.ReadFile(path); err == nil && !strings.HasPrefix(string(data), scriptHead) {
+			skipped = append(skipped, fmt.Sprintf("%s: %s already exists", e.name, path))
+			continue
+		} else if err != nil && !os.IsNotExist(err) {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", e.name, err))
+			continue
+		}
+		if err := os.WriteFile(path, []byte(scriptHead+e.header()+body), 0755); err != nil {
+			return skipped, err
+		}
+		// WriteFile keeps the mode of an existing file.
+		if err := os.Chmod(path, 0755); err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+// ShellExportResult is where ExportShell wrote to and, for a folder of
+// scripts, which shortcuts it left out and why.
+type ShellExportResult struct {
+	Path    string   `json:"path"` // "" if the user cancelled
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// RenderShellExport renders the shortcuts in one of the single-file formats
+// (ExportBash, ExportFish or ExportPowerShell). Shortcuts that cannot be
+// converted are listed as comments.
+func RenderShellExport(format string, shortcuts map[string]ShortcutData) (string, error) {
+	list := prepareExport(shortcuts)
+	switch format {
+	case ExportBash:
+		return renderBash(list), nil
+	case ExportFish:
+		return renderFish(list), nil
+	case ExportPowerShell:
+		return renderPowerShell(list), nil
+	}
+	return "", fmt.Errorf("unknown export format %q", format)
+}
+
+// ExportShell asks for a destination and exports the library in format. For
+// ExportScripts it asks for a folder.
+func ExportShell(ctx context.Context, format string) (res ShellExportResult, err error) {
+	shortcuts, err := GetShortcuts()
+	if err != nil {
+		return res, err
+	}
+	if format == ExportScripts {
+		dir, err := runtime.OpenDirectoryDialog(ctx, runtime.OpenDialogOptions{
+			Title:                "Export Shortcuts as Scripts",
+			CanCreateDirectories: true,
+		})
+		if err != nil || dir == "" {
+			return res, err
+		}
+		res.Path = dir
+		res.Skipped, err = writeScripts(dir, prepareExport(shortcuts))
+		return res, err
+	}
+
+	content, err := RenderShellExport(format, shortcuts)
+	if err != nil {
+		return res, err
+	}
+	filename := map[string]string{
+		ExportBash:       "ya-aliases.sh",
+		ExportFish:       "ya.fish",
+		ExportPowerShell: "ya-profile.ps1",
+	}[format]
+	dest, err := runtime.SaveFileDialog(ctx, runtime.SaveDialogOptions{
+		Title:           "Export Shortcuts",
+		DefaultFilename: filename,
+	})
+	if err != nil || dest == "" {
+		return res, err
+	}
+	res.Path = dest
+	return res, os.WriteFile(dest, []byte(content), 0644)
+}
@@ -3,6 +3,8 @@
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -20,6 +22,23 @@ type shortcutMeta struct {
 	Pinned      bool     `json:"pinned,omitempty"`
 	RunCount    int      `json:"runCount,omitempty"`
 	LastRun     string   `json:"lastRun,omitempty"`
+	// VarHistory holds recently used values per placeholder, newest first.
+	VarHistory map[string][]string `json:"varHistory,omitempty"`
+}
+
+// metaOf extracts the GUI-only metadata of s. ok is false when there is
+// nothing worth storing.
+func metaOf(s ShortcutData) (m shortcutMeta, ok bool) {
+	m = shortcutMeta{
+		Description: s.Description,
+		Tags:        s.Tags,
+		Pinned:      s.Pinned,
+		RunCount:    s.RunCount,
+		LastRun:     s.LastRun,
+		VarHistory:  s.VarHistory,
+	}
+	ok = s.Description != "" || len(s.Tags) > 0 || s.Pinned || s.RunCount > 0 || len(s.VarHistory) > 0
+	return m, ok
 }
 
 //  file paths
@@ -40,6 +59,14 @@ func metaFilePath() (string, error) {
 	return filepath.Join(appDir, "shortcuts-meta.json"), nil
 }
 
+func pendingSaveFilePath() (string, error) {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, "shortcuts.pending.json"), nil
+}
+
 //  commands (CLI-compatible map[string]string)
 
 // loadCommands reads shortcuts.json as map[string]string.
@@ -77,14 +104,8 @@ func loadCommands() (map[string]string, error) {
 	meta := make(map[string]shortcutMeta, len(rich))
 	for name, s := range rich {
 		cmds[name] = s.Command
-		if s.Description != "" || len(s.Tags) > 0 || s.Pinned || s.RunCount > 0 {
-			meta[name] = shortcutMeta{
-				Description: s.Description,
-				Tags:        s.Tags,
-				Pinned:      s.Pinned,
-				RunCount:    s.RunCount,
-				LastRun:     s.LastRun,
-			}
+		if m, ok := metaOf(s); ok {
+			meta[name] = m
 		}
 	}
 	// Persist both files in the correct format.
@@ -102,7 +123,7 @@ func saveCommands(cmds map[string]string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
 
 //  metadata (GUI-only)
@@ -136,12 +157,88 @@ func saveMeta(meta map[string]shortcutMeta) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
 
 //  combined load / save
 
+// pendingSave is written before saveShortcuts touches either store so that
+// a save interrupted between shortcuts.json and shortcuts-meta.json can be
+// completed on the next load. The file contents are kept byte-for-byte.
+type pendingSave struct {
+	Commands []byte `json:"commands"`
+	Meta     []byte `json:"meta"`
+	// BaseSum is the SHA-256 of shortcuts.json before the save started. It
+	// tells an unfinished save apart from a later write by the CLI.
+	BaseSum string `json:"baseSum"`
+}
+
+func fileSum(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return dataSum(data)
+}
+
+func dataSum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// recoverPendingSave completes a saveShortcuts that was interrupted, e.g. by
+// a crash or power loss. The save is only replayed if the pending file is
+// complete and neither store has been written since, apart from
+// shortcuts.json already holding the save's own commands; otherwise it is
+// discarded.
+func recoverPendingSave() error {
+	pendingPath, err := pendingSaveFilePath()
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(pendingPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	data, err := os.ReadFile(pendingPath)
+	if err != nil {
+		return err
+	}
+	cmdPath, err := shortcutFilePath()
+	if err != nil {
+		return err
+	}
+	metaPath, err := metaFilePath()
+	if err != nil {
+		return err
+	}
+	untouched := func(path string) bool {
+		st, err := os.Stat(path)
+		return err != nil || !st.ModTime().After(info.ModTime())
+	}
+
+	var p pendingSave
+	if json.Unmarshal(data, &p) == nil && p.Commands != nil && p.Meta != nil && untouched(metaPath) {
+		sum := fileSum(cmdPath)
+		if sum == dataSum(p.Commands) || (sum == p.BaseSum && untouched(cmdPath)) {
+			if err := writeFileAtomic(cmdPath, p.Commands, 0644); err != nil {
+				return err
+			}
+			if err := writeFileAtomic(metaPath, p.Meta, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Remove(pendingPath)
+}
+
 func loadShortcuts() (map[string]ShortcutData, error) {
+	if err := recoverPendingSave(); err != nil {
+		return nil, err
+	}
 	cmds, err := loadCommands()
 	if err != nil {
 		return nil, err
@@ -150,6 +247,11 @@ func loadShortcuts() (map[string]ShortcutData, error) {
 	if err != nil {
 		return nil, err
 	}
+	return mergeShortcuts(cmds, meta), nil
+}
+
+// mergeShortcuts joins the CLI commands with the GUI metadata.
+func mergeShortcuts(cmds map[string]string, meta map[string]shortcutMeta) map[string]ShortcutData {
 	result := make(map[string]ShortcutData, len(cmds))
 	for name, cmd := range cmds {
 		m := meta[name]
@@ -160,9 +262,10 @@ func loadShortcuts() (map[string]ShortcutData, error) {
 			Pinned:      m.Pinned,
 			RunCount:    m.RunCount,
 			LastRun:     m.LastRun,
+			VarHistory:  m.VarHistory,
 		}
 	}
-	return result, nil
+	return result
 }
 
 func saveShortcuts(shortcuts map[string]ShortcutData) error {
@@ -170,143 +273,258 @@ func saveShortcuts(shortcuts map[string]ShortcutData) error {
 	meta := make(map[string]shortcutMeta, len(shortcuts))
 	for name, s := range shortcuts {
 		cmds[name] = s.Command
-		if s.Description != "" || len(s.Tags) > 0 || s.Pinned || s.RunCount > 0 {
-			meta[name] = shortcutMeta{
-				Description: s.Description,
-				Tags:        s.Tags,
-				Pinned:      s.Pinned,
-				RunCount:    s.RunCount,
-				LastRun:     s.LastRun,
-			}
+		if m, ok := metaOf(s); ok {
+			meta[name] = m
 		}
 	}
+	cmdPath, err := shortcutFilePath()
+	if err != nil {
+		return err
+	}
+	pendingPath, err := pendingSaveFilePath()
+	if err != nil {
+		return err
+	}
+	p := pendingSave{BaseSum: fileSum(cmdPath)}
+	if p.Commands, err = json.MarshalIndent(cmds, "", "  "); err != nil {
+		return err
+	}
+	if p.Meta, err = json.MarshalIndent(meta, "", "  "); err != nil {
+		return err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(pendingPath, data, 0644); err != nil {
+		return err
+	}
+	// A save reported as failed must not be replayed on the next load.
 	if err := saveCommands(cmds); err != nil {
+		_ = os.Remove(pendingPath)
+		return err
+	}
+	if err := saveMeta(meta); err != nil {
+		_ = os.Remove(pendingPath)
 		return err
 	}
-	return saveMeta(meta)
+	return os.Remove(pendingPath)
 }
 
 //  public API
 
 // GetShortcuts returns all shortcuts merged with their metadata.
-func GetShortcuts() (map[string]ShortcutData, error) {
-	return loadShortcuts()
+func GetShortcuts() (shortcuts map[string]ShortcutData, err error) {
+	err = withDataLock(func() error {
+		shortcuts, err = loadShortcuts()
+		return err
+	})
+	return shortcuts, err
 }
 
-// AddShortcut creates or replaces a shortcut. tags is a comma-separated list.
-func AddShortcut(name, command, description, tags string) error {
-	shortcuts, err := loadShortcuts()
+// GetShortcut returns a single shortcut by name.
+func GetShortcut(name string) (ShortcutData, error) {
+	shortcuts, err := GetShortcuts()
 	if err != nil {
-		return err
+		return ShortcutData{}, err
 	}
-	existing := shortcuts[name]
-	shortcuts[name] = ShortcutData{
-		Command:     command,
-		Description: description,
-		Tags:        parseTags(tags),
-		Pinned:      existing.Pinned,
-		RunCount:    existing.RunCount,
-		LastRun:     existing.LastRun,
+	s, ok := shortcuts[name]
+	if !ok {
+		return ShortcutData{}, fmt.Errorf("shortcut %q not found", name)
 	}
-	return saveShortcuts(shortcuts)
+	return s, nil
+}
+
+// AddShortcut creates or replaces a shortcut. tags is a comma-separated list.
+func AddShortcut(name, command, description, tags string) error {
+	return updateShortcuts(fmt.Sprintf("Save %q", name), func(shortcuts map[string]ShortcutData) error {
+		existing := shortcuts[name]
+		shortcuts[name] = ShortcutData{
+			Command:     command,
+			Description: description,
+			Tags:        parseTags(tags),
+			Pinned:      existing.Pinned,
+			RunCount:    existing.RunCount,
+			LastRun:     existing.LastRun,
+			VarHistory:  existing.VarHistory,
+		}
+		return nil
+	})
 }
 
 // UpdateShortcut edits an existing shortcut. If newName differs from oldName
-// the shortcut is renamed atomically, preserving Pinned, RunCount and LastRun.
+// the shortcut is renamed atomically, preserving Pinned, RunCount, LastRun
+// and VarHistory.
 func UpdateShortcut(oldName, newName, command, description, tags string) error {
 	newName = strings.TrimSpace(newName)
 	if newName == "" {
 		return fmt.Errorf("shortcut name cannot be empty")
 	}
-	shortcuts, err := loadShortcuts()
-	if err != nil {
-		return err
-	}
-	src, ok := shortcuts[oldName]
-	if !ok {
-		return fmt.Errorf("shortcut %q not found", oldName)
-	}
+	op := fmt.Sprintf("Edit %q", newName)
 	if oldName != newName {
-		if _, exists := shortcuts[newName]; exists {
-			return fmt.Errorf("a shortcut named %q already exists", newName)
-		}
-		delete(shortcuts, oldName)
+		op = fmt.Sprintf("Rename %q to %q", oldName, newName)
 	}
-	shortcuts[newName] = ShortcutData{
-		Command:     command,
-		Description: description,
-		Tags:        parseTags(tags),
-		Pinned:      src.Pinned,
-		RunCount:    src.RunCount,
-		LastRun:     src.LastRun,
-	}
-	return saveShortcuts(shortcuts)
+	return updateShortcuts(op, func(shortcuts map[string]ShortcutData) error {
+		src, ok := shortcuts[oldName]
+		if !ok {
+			return fmt.Errorf("shortcut %q not found", oldName)
+		}
+		if oldName != newName {
+			if _, exists := shortcuts[newName]; exists {
+				return fmt.Errorf("a shortcut named %q already exists", newName)
+			}
+			delete(shortcuts, oldName)
+		}
+		shortcuts[newName] = ShortcutData{
+			Command:     command,
+			Description: description,
+			Tags:        parseTags(tags),
+			Pinned:      src.Pinned,
+			RunCount:    src.RunCount,
+			LastRun:     src.LastRun,
+			VarHistory:  src.VarHistory,
+		}
+		return nil
+	})
 }
 
-// RemoveShortcut deletes a shortcut and its metadata by name.
+// RemoveShortcut moves a shortcut and its metadata to the trash.
 func RemoveShortcut(name string) error {
-	shortcuts, err := loadShortcuts()
-	if err != nil {
-		return err
-	}
-	delete(shortcuts, name)
-	return saveShortcuts(shortcuts)
+	return withDataLock(func() error {
+		shortcuts, err := loadShortcuts()
+		if err != nil {
+			return err
+		}
+		s, ok := shortcuts[name]
+		if !ok {
+			return nil
+		}
+		before := cloneShortcuts(shortcuts)
+		delete(shortcuts, name)
+		// Trash first so the shortcut is never only gone; if it stays in
+		// the library after all, take it back out of the trash.
+		if err := trashShortcut(name, s); err != nil {
+			return err
+		}
+		if err := commitShortcuts(before, shortcuts); err != nil {
+			_ = untrashShortcut(name, s)
+			return err
+		}
+		changes := diffShortcuts(before, shortcuts)
+		for i := range changes {
+			changes[i].Trashed = true
+		}
+		return recordChange(fmt.Sprintf("Delete %q", name), changes)
+	})
 }
 
 // TogglePinShortcut flips the Pinned flag on a shortcut.
 func TogglePinShortcut(name string) error {
-	shortcuts, err := loadShortcuts()
-	if err != nil {
-		return err
-	}
-	s, ok := shortcuts[name]
-	if !ok {
+	return updateShortcuts(fmt.Sprintf("Toggle pin on %q", name), func(shortcuts map[string]ShortcutData) error {
+		s, ok := shortcuts[name]
+		if !ok {
+			return errUnchanged
+		}
+		s.Pinned = !s.Pinned
+		shortcuts[name] = s
 		return nil
-	}
-	s.Pinned = !s.Pinned
-	shortcuts[name] = s
-	return saveShortcuts(shortcuts)
+	})
 }
 
 // DuplicateShortcut creates a copy of a shortcut with " (copy)" appended.
-func DuplicateShortcut(name string) (map[string]ShortcutData, error) {
-	shortcuts, err := loadShortcuts()
+func DuplicateShortcut(name string) (result map[string]ShortcutData, err error) {
+	err = updateShortcuts(fmt.Sprintf("Duplicate %q", name), func(shortcuts map[string]ShortcutData) error {
+		result = shortcuts
+		src, ok := shortcuts[name]
+		if !ok {
+			return errUnchanged
+		}
+		copyName := name + " (copy)"
+		for i := 2; ; i++ {
+			if _, exists := shortcuts[copyName]; !exists {
+				break
+			}
+			copyName = fmt.Sprintf("%s (copy %d)", name, i)
+		}
+		shortcuts[copyName] = ShortcutData{
+			Command:     src.Command,
+			Description: src.Description,
+			Tags:        append([]string(nil), src.Tags...),
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	src, ok := shortcuts[name]
-	if !ok {
-		return shortcuts, nil
-	}
-	copyName := name + " (copy)"
-	for i := 2; ; i++ {
-		if _, exists := shortcuts[copyName]; !exists {
-			break
-		}
-		copyName = fmt.Sprintf("%s (copy %d)", name, i)
-	}
-	shortcuts[copyName] = ShortcutData{
-		Command:     src.Command,
-		Description: src.Description,
-		Tags:        append([]string(nil), src.Tags...),
-	}
-	return shortcuts, saveShortcuts(shortcuts)
+	return result, nil
 }
 
 // IncrementRunCount bumps RunCount and records the current time as LastRun.
 func IncrementRunCount(name string) error {
-	shortcuts, err := loadShortcuts()
+	return updateShortcuts("", func(shortcuts map[string]ShortcutData) error {
+		s, ok := shortcuts[name]
+		if !ok {
+			return errUnchanged
+		}
+		s.RunCount++
+		s.LastRun = time.Now().UTC().Format(time.RFC3339)
+		shortcuts[name] = s
+		return nil
+	})
+}
+
+// maxVarHistory is how many recent values are kept per placeholder.
+const maxVarHistory = 10
+
+// RecordVariableValues remembers the non-empty values used for name's
+// placeholders so they can be offered again next time.
+func RecordVariableValues(name string, values map[string]string) error {
+	return updateShortcuts("", func(shortcuts map[string]ShortcutData) error {
+		s, ok := shortcuts[name]
+		if !ok {
+			return errUnchanged
+		}
+		defs, err := ParsePlaceholders(s.Command)
+		if err != nil {
+			return err
+		}
+		changed := false
+		for _, ph := range defs {
+			v := values[ph.Name]
+			if v == "" {
+				continue
+			}
+			if s.VarHistory == nil {
+				s.VarHistory = map[string][]string{}
+			}
+			recent := []string{v}
+			for _, old := range s.VarHistory[ph.Name] {
+				if old != v && len(recent) < maxVarHistory {
+					recent = append(recent, old)
+				}
+			}
+			s.VarHistory[ph.Name] = recent
+			changed = true
+		}
+		if !changed {
+			return errUnchanged
+		}
+		shortcuts[name] = s
+		return nil
+	})
+}
+
+// GetVariableHistory returns the recently used values of name's
+// placeholders, newest first.
+func GetVariableHistory(name string) (map[string][]string, error) {
+	s, err := GetShortcut(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	s, ok := shortcuts[name]
-	if !ok {
-		return nil
+	if s.VarHistory == nil {
+		return map[string][]string{}, nil
 	}
-	s.RunCount++
-	s.LastRun = time.Now().UTC().Format(time.RFC3339)
-	shortcuts[name] = s
-	return saveShortcuts(shortcuts)
+	return s.VarHistory, nil
 }
 
 // ExportShortcuts opens a save dialog and writes the CLI-compatible shortcuts.json.
@@ -332,47 +550,25 @@ func ExportShortcuts(ctx context.Context) error {
 	return os.WriteFile(dest, data, 0644)
 }
 
-// ImportShortcuts opens an open dialog, reads the file, and merges shortcuts.
+// ImportShortcuts opens an open dialog and merges the chosen file without a
+// preview, taking the incoming side of every conflict.
 // Accepts both the CLI format (map[string]string) and the old GUI object format.
 func ImportShortcuts(ctx context.Context) error {
-	path, err := runtime.OpenFileDialog(ctx, runtime.OpenDialogOptions{
-		Title: "Import Shortcuts",
-		Filters: []runtime.FileFilter{
-			{DisplayName: "JSON Files", Pattern: "*.json"},
-		},
-	})
+	path, err := SelectImportFile(ctx)
 	if err != nil || path == "" {
 		return err
 	}
-	data, err := os.ReadFile(path)
+	plan, err := PreviewImport(path)
 	if err != nil {
 		return err
 	}
-
-	// Try CLI format first.
-	var imported map[string]string
-	if json.Unmarshal(data, &imported) != nil {
-		// Fall back to old GUI object format.
-		var rich map[string]ShortcutData
-		if err := json.Unmarshal(data, &rich); err != nil {
-			return err
-		}
-		imported = make(map[string]string, len(rich))
-		for n, s := range rich {
-			imported[n] = s.Command
+	for i := range plan.Items {
+		if plan.Items[i].Status != ImportIdentical {
+			plan.Items[i].Action = ImportTheirs
 		}
 	}
-
-	current, err := loadShortcuts()
-	if err != nil {
-		return err
-	}
-	for name, cmd := range imported {
-		existing := current[name]
-		existing.Command = cmd
-		current[name] = existing
-	}
-	return saveShortcuts(current)
+	_, err = ApplyImport(plan)
+	return err
 }
 
 // parseTags splits a comma-separated tag string into a trimmed slice.
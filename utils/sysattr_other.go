@@ -2,8 +2,66 @@
 
 package utils
 
-import "os/exec"
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
 
 func hideWindowForCmd(cmd *exec.Cmd) {
 	// no-op: HideWindow is Windows-only
 }
+
+// setProcessGroup starts cmd in its own process group so that it and every
+// child it spawns can be signalled together.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// terminateProcessTree asks the process group led by pid to exit (SIGTERM).
+func terminateProcessTree(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGTERM)
+}
+
+// killProcessTree forcibly kills the process group led by pid (SIGKILL).
+func killProcessTree(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// pollableFile replaces f with a non-blocking copy of its descriptor, so
+// that closing it interrupts a Read in progress. f is returned unchanged if
+// that fails.
+func pollableFile(f *os.File) *os.File {
+	fd, err := syscall.Dup(int(f.Fd()))
+	if err != nil {
+		return f
+	}
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		_ = syscall.Close(fd)
+		return f
+	}
+	_ = f.Close()
+	return os.NewFile(uintptr(fd), f.Name())
+}
+
+// setTerminalSize resizes the pseudo-terminal f without taking its
+// descriptor out of non-blocking mode, as f.Fd() would.
+func setTerminalSize(f *os.File, cols, rows uint16) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ioctlErr error
+	err = conn.Control(func(fd uintptr) {
+		ioctlErr = unix.IoctlSetWinsize(int(fd), unix.TIOCSWINSZ, &unix.Winsize{Row: rows, Col: cols})
+	})
+	if err != nil {
+		return err
+	}
+	return ioctlErr
+}
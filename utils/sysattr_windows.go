@@ -3,10 +3,47 @@
 package utils
 
 import (
+	"errors"
+	"os"
 	"os/exec"
+	"strconv"
 	"syscall"
 )
 
 func hideWindowForCmd(cmd *exec.Cmd) {
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.HideWindow = true
+}
+
+// setProcessGroup starts cmd in a new process group so that it and every
+// child it spawns can be terminated together.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// terminateProcessTree asks the process tree rooted at pid to close.
+func terminateProcessTree(pid int) error {
+	cmd := exec.Command("taskkill", "/T", "/PID", strconv.Itoa(pid))
+	hideWindowForCmd(cmd)
+	return cmd.Run()
+}
+
+// killProcessTree forcibly kills the process tree rooted at pid.
+func killProcessTree(pid int) error {
+	cmd := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid))
+	hideWindowForCmd(cmd)
+	return cmd.Run()
+}
+
+// pollableFile and setTerminalSize serve terminal sessions, which Windows
+// does not have.
+func pollableFile(f *os.File) *os.File { return f }
+
+func setTerminalSize(f *os.File, cols, rows uint16) error {
+	return errors.New("embedded terminal sessions are not supported on Windows")
 }
@@ -2,94 +2,212 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	goRuntime "runtime"
+	"strings"
 )
 
-// LaunchInTerminal opens a new terminal window in dirPath and runs command.
-// preferredTerminal may be "auto", "wt", "powershell", "cmd", or "bash".
-func LaunchInTerminal(command, dirPath, preferredTerminal string) error {
+// Built-in launchers, in the order "auto" tries them. POSIX commands are
+// followed by `exec {shell}` so the window stays open once they finish.
+var (
+	windowsTerminals = []TerminalLauncher{
+		{Name: "wt", Label: "Windows Terminal (wt)", Shell: ShellPowerShell,
+			Args: []string{"wt", "-d", "{dir}", "{shell}", "-NoExit", "-Command", "{command}"}},
+		{Name: "powershell", Label: "PowerShell", Shell: ShellPowerShell,
+			Args: []string{"{shell}", "-NoExit", "-Command", "{command}"}},
+		{Name: "cmd", Label: "Command Prompt (cmd)", Shell: ShellCmd,
+			Args: []string{"{shell}", "/K", "{command}"}},
+	}
+	unixTerminals = []TerminalLauncher{
+		{Name: "gnome-terminal", Label: "GNOME Terminal", Shell: ShellPOSIX,
+			Args: []string{"gnome-terminal", "--", "{shell}", "-c", "{command}; exec {shell}"}},
+		{Name: "x-terminal-emulator", Label: "System default (x-terminal-emulator)", Shell: ShellPOSIX,
+			Args: []string{"x-terminal-emulator", "-e", "{shell}", "-c", "{command}; exec {shell}"}},
+		{Name: "konsole", Label: "Konsole", Shell: ShellPOSIX,
+			Args: []string{"konsole", "-e", "{shell}", "-c", "{command}; exec {shell}"}},
+		{Name: "xterm", Label: "xterm", Shell: ShellPOSIX,
+			Args: []string{"xterm", "-e", "{shell}", "-c", "{command}; exec {shell}"}},
+		{Name: "bash", Label: "Bash (no new window)", Shell: ShellPOSIX,
+			Args: []string{"{shell}", "-c", "{command}; exec {shell}"}},
+	}
+)
+
+// shellPrograms is what {shell} stands for in each dialect.
+var shellPrograms = map[string]string{
+	ShellPOSIX:      "bash",
+	ShellPowerShell: "powershell",
+	ShellCmd:        "cmd",
+}
+
+var terminalSlot = regexp.MustCompile(`\{(\w+)\}`)
+
+// builtInTerminals returns the launchers shipped for this OS.
+func builtInTerminals() []TerminalLauncher {
 	if goRuntime.GOOS == "windows" {
-		return launchWindows(command, dirPath, preferredTerminal)
+		return windowsTerminals
 	}
-	return launchUnix(command, dirPath, preferredTerminal)
+	return unixTerminals
 }
 
-func launchWindows(command, dirPath, preferred string) error {
-	// Resolve which terminal to use.
-	type launcher struct {
-		key string
-		try func() (*exec.Cmd, bool)
+// TerminalLaunchers returns the built-in launchers for this OS followed by
+// the user's custom ones. Custom launchers that fail validation, as a
+// hand-edited config.json can make them, are left out.
+func TerminalLaunchers() ([]TerminalLauncher, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return nil, err
 	}
+	return terminalLaunchers(cfg.CustomTerminals), nil
+}
 
-	wtLauncher := func() (*exec.Cmd, bool) {
-		p, err := exec.LookPath("wt")
-		if err != nil {
-			return nil, false
+func terminalLaunchers(custom []TerminalLauncher) []TerminalLauncher {
+	var all []TerminalLauncher
+	for _, t := range builtInTerminals() {
+		t.BuiltIn = true
+		all = append(all, t)
+	}
+	for _, t := range custom {
+		if validateTerminal(t) == nil {
+			all = append(all, t)
 		}
-		return exec.Command(p, "-d", dirPath, "powershell", "-NoExit", "-Command", command), true
 	}
-	psLauncher := func() (*exec.Cmd, bool) {
-		p, err := exec.LookPath("powershell")
-		if err != nil {
-			return nil, false
+	return all
+}
+
+// LaunchInTerminal opens a new terminal window in dirPath and runs command.
+// preferredTerminal is "auto" or the name of a launcher from
+// TerminalLaunchers. The launched process is tracked under id until it
+// exits, at which point onExit (if non-nil) receives its exit status. The
+// name of the terminal program that was actually started is returned.
+func LaunchInTerminal(id, shortcutName, command, dirPath, preferredTerminal string, onExit func(RunExit)) (string, error) {
+	t, program, err := resolveTerminal(preferredTerminal)
+	if err != nil {
+		return "", err
+	}
+	args := expandTerminalArgs(t, dirPath, command)
+	cmd := exec.Command(program, args[1:]...)
+	cmd.Dir = dirPath
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	trackProcess(id, shortcutName, dirPath, "terminal", cmd)
+	waitProcess(id, cmd, onExit)
+	return strings.TrimSuffix(filepath.Base(cmd.Path), ".exe"), nil
+}
+
+// TerminalShell reports which shell dialect LaunchInTerminal will run
+// commands in for preferredTerminal, so substituted values can be quoted
+// for it.
+func TerminalShell(preferredTerminal string) string {
+	t, _, err := resolveTerminal(preferredTerminal)
+	if err != nil {
+		return DefaultShell()
+	}
+	return terminalShell(t)
+}
+
+func terminalShell(t TerminalLauncher) string {
+	if t.Shell == "" {
+		return DefaultShell()
+	}
+	return t.Shell
+}
+
+// resolveTerminal picks the launcher to use for preferred and the path of
+// its program. A preferred built-in falls back to the built-ins after it,
+// and a custom launcher (or "auto") to all of them, skipping any whose
+// program is not installed.
+func resolveTerminal(preferred string) (TerminalLauncher, string, error) {
+	cfg, _ := GetConfig()
+	all := terminalLaunchers(cfg.CustomTerminals)
+	builtIns := all[:len(builtInTerminals())]
+
+	candidates := builtIns
+	for i, t := range all {
+		if t.Name != preferred {
+			continue
 		}
-		c := exec.Command(p, "-NoExit", "-Command", command)
-		c.Dir = dirPath
-		return c, true
-	}
-	cmdLauncher := func() (*exec.Cmd, bool) {
-		p, err := exec.LookPath("cmd")
-		if err != nil {
-			return nil, false
+		if t.BuiltIn {
+			candidates = builtIns[i:]
+		} else {
+			candidates = append([]TerminalLauncher{t}, builtIns...)
 		}
-		c := exec.Command(p, "/K", command)
-		c.Dir = dirPath
-		return c, true
-	}
-
-	var order []func() (*exec.Cmd, bool)
-	switch preferred {
-	case "wt":
-		order = []func() (*exec.Cmd, bool){wtLauncher, psLauncher, cmdLauncher}
-	case "powershell":
-		order = []func() (*exec.Cmd, bool){psLauncher, cmdLauncher}
-	case "cmd":
-		order = []func() (*exec.Cmd, bool){cmdLauncher}
-	default: // "auto"
-		order = []func() (*exec.Cmd, bool){wtLauncher, psLauncher, cmdLauncher}
-	}
-
-	for _, fn := range order {
-		if cmd, ok := fn(); ok {
-			return cmd.Start()
+		break
+	}
+
+	var tried []string
+	for _, t := range candidates {
+		program := expandTerminalArgs(t, "", "")[0]
+		if p, err := exec.LookPath(program); err == nil {
+			return t, p, nil
 		}
+		tried = append(tried, t.Name)
 	}
-	return errors.New("no suitable terminal found (wt, powershell, or cmd)")
+	return TerminalLauncher{}, "", fmt.Errorf("no suitable terminal found (%s)", strings.Join(tried, ", "))
 }
 
-func launchUnix(command, dirPath, preferred string) error {
-	candidates := []string{"gnome-terminal", "x-terminal-emulator", "konsole", "xterm"}
-	if preferred == "bash" {
-		candidates = nil // skip GUI terminals, use bash directly
+// expandTerminalArgs fills t's argv template in.
+func expandTerminalArgs(t TerminalLauncher, dir, command string) []string {
+	r := strings.NewReplacer("{shell}", shellPrograms[terminalShell(t)], "{dir}", dir, "{command}", command)
+	args := make([]string, len(t.Args))
+	for i, a := range t.Args {
+		args[i] = r.Replace(a)
 	}
+	return args
+}
 
-	for _, t := range candidates {
-		p, err := exec.LookPath(t)
-		if err != nil {
-			continue
-		}
-		cmd := exec.Command(p, "--", "bash", "-c", command+"; exec bash")
-		cmd.Dir = dirPath
-		return cmd.Start()
+// ParseTerminalTemplate splits a launcher's command line, as typed in
+// Settings, into an argv template. Quoting follows POSIX shells, or
+// PowerShell on Windows so that backslashes in paths are kept.
+func ParseTerminalTemplate(line string) ([]string, error) {
+	dialect := dialectPOSIX
+	if goRuntime.GOOS == "windows" {
+		dialect = dialectPowerShell
 	}
+	args, ok := shellWords(line, dialect)
+	if !ok {
+		return nil, errors.New("unbalanced quotes in terminal command")
+	}
+	return args, nil
+}
 
-	// Fallback: bash in-place
-	p, err := exec.LookPath("bash")
-	if err != nil {
-		return errors.New("no suitable terminal found")
+// validateTerminal checks a custom launcher before it is saved.
+func validateTerminal(t TerminalLauncher) error {
+	if t.Name == "" {
+		return errors.New("terminal name is required")
 	}
-	cmd := exec.Command(p, "-c", command+"; exec bash")
-	cmd.Dir = dirPath
-	return cmd.Start()
+	if t.Name == "auto" {
+		return fmt.Errorf("%q is reserved", t.Name)
+	}
+	for _, b := range append(windowsTerminals, unixTerminals...) {
+		if b.Name == t.Name {
+			return fmt.Errorf("%q is the name of a built-in terminal", t.Name)
+		}
+	}
+	if len(t.Args) == 0 || t.Args[0] == "" {
+		return errors.New("terminal command is required")
+	}
+	if _, ok := shellPrograms[terminalShell(t)]; !ok {
+		return fmt.Errorf("unknown shell %q (use posix, powershell or cmd)", t.Shell)
+	}
+	hasCommand := false
+	for _, a := range t.Args {
+		for _, m := range terminalSlot.FindAllStringSubmatch(a, -1) {
+			switch m[1] {
+			case "command":
+				hasCommand = true
+			case "shell", "dir":
+			default:
+				return fmt.Errorf("unknown slot {%s} (use {shell}, {dir} or {command})", m[1])
+			}
+		}
+	}
+	if !hasCommand {
+		return errors.New("terminal command must include {command}")
+	}
+	return nil
 }
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultTrashRetentionDays applies when AppConfig.TrashRetentionDays is 0.
+const defaultTrashRetentionDays = 30
+
+// TrashEntry is a deleted shortcut kept for restoring.
+type TrashEntry struct {
+	Name      string       `json:"name"`
+	Shortcut  ShortcutData `json:"shortcut"`
+	DeletedAt string       `json:"deletedAt"`
+}
+
+func trashFilePath() (string, error) {
+	appDir, err := getAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, "trash.json"), nil
+}
+
+func loadTrash() ([]TrashEntry, error) {
+	path, err := trashFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []TrashEntry{}, nil
+		}
+		return nil, err
+	}
+	var entries []TrashEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func saveTrash(entries []TrashEntry) error {
+	path, err := trashFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+// trashShortcut adds s to the trash. It must be called under the data lock.
+func trashShortcut(name string, s ShortcutData) error {
+	entries, err := loadTrash()
+	if err != nil {
+		return err
+	}
+	entries = append(entries, TrashEntry{
+		Name:      name,
+		Shortcut:  s,
+		DeletedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	return saveTrash(entries)
+}
+
+// untrashShortcut drops the newest trash entry for name whose content matches
+// s, if any. It must be called under the data lock.
+func untrashShortcut(name string, s ShortcutData) error {
+	entries, err := loadTrash()
+	if err != nil {
+		return err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Name == name && sameContent(entries[i].Shortcut, s) {
+			return saveTrash(append(entries[:i], entries[i+1:]...))
+		}
+	}
+	return nil
+}
+
+// purgeTrash drops entries older than the configured retention. It must be
+// called under the data lock.
+func purgeTrash() ([]TrashEntry, error) {
+	entries, err := loadTrash()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	days := cfg.TrashRetentionDays
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	kept := entries[:0]
+	for _, e := range entries {
+		if t, err := time.Parse(time.RFC3339, e.DeletedAt); err == nil && t.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if len(kept) == len(entries) {
+		return entries, nil
+	}
+	return kept, saveTrash(kept)
+}
+
+// PurgeTrash permanently deletes trash entries past the retention period.
+func PurgeTrash() error {
+	return withDataLock(func() error {
+		_, err := purgeTrash()
+		return err
+	})
+}
+
+// ListTrash returns the deleted shortcuts, most recently deleted first.
+func ListTrash() (entries []TrashEntry, err error) {
+	err = withDataLock(func() error {
+		entries, err = purgeTrash()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// RestoreFromTrash puts the most recently deleted shortcut called name back
+// into the library and returns the name it was restored under: if the name
+// has been taken since, " (restored)" is appended.
+func RestoreFromTrash(name string) (restored string, err error) {
+	err = withDataLock(func() error {
+		entries, err := loadTrash()
+		if err != nil {
+			return err
+		}
+		idx := -1
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entries[i].Name == name {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return fmt.Errorf("shortcut %q is not in the trash", name)
+		}
+		entry := entries[idx]
+
+		shortcuts, err := loadShortcuts()
+		if err != nil {
+			return err
+		}
+		restored = name
+		for i := 1; ; i++ {
+			if _, exists := shortcuts[restored]; !exists {
+				break
+			}
+			restored = name + " (restored)"
+			if i > 1 {
+				restored = fmt.Sprintf("%s (restored %d)", name, i)
+			}
+		}
+		before := cloneShortcuts(shortcuts)
+		shortcuts[restored] = entry.Shortcut
+		if err := commitShortcuts(before, shortcuts); err != nil {
+			return err
+		}
+		if err := saveTrash(append(entries[:idx], entries[idx+1:]...)); err != nil {
+			return err
+		}
+		changes := diffShortcuts(before, shortcuts)
+		for i := range changes {
+			changes[i].Trashed = true
+		}
+		return recordChange(fmt.Sprintf("Restore %q from trash", name), changes)
+	})
+	return restored, err
+}
+
+// EmptyTrash permanently deletes everything in the trash.
+func EmptyTrash() error {
+	return withDataLock(func() error {
+		return saveTrash([]TrashEntry{})
+	})
+}
@@ -8,14 +8,58 @@ type ShortcutData struct {
 	Pinned      bool     `json:"pinned,omitempty"`
 	RunCount    int      `json:"runCount,omitempty"`
 	LastRun     string   `json:"lastRun,omitempty"`
+	// VarHistory holds recently used values per placeholder, newest first.
+	VarHistory map[string][]string `json:"varHistory,omitempty"`
+	// Source is the .ya.json a project shortcut comes from; it is empty for
+	// the global library and never saved.
+	Source string `json:"source,omitempty"`
 }
 
 // AppConfig holds all application-level settings.
 type AppConfig struct {
 	DefaultDir        string     `json:"defaultDir,omitempty"`
-	PreferredTerminal string     `json:"preferredTerminal,omitempty"` // "auto" | "wt" | "powershell" | "cmd" | "bash"
+	PreferredTerminal string     `json:"preferredTerminal,omitempty"` // "auto" or the name of a TerminalLauncher
 	StartOnBoot       bool       `json:"startOnBoot,omitempty"`
 	SavedDirectories  []SavedDir `json:"savedDirectories,omitempty"`
+	// TrashRetentionDays is how long deleted shortcuts stay in the trash;
+	// 0 means 30 days.
+	TrashRetentionDays int `json:"trashRetentionDays,omitempty"`
+	// EnableAPI starts the local control API (see StartControlServer).
+	EnableAPI bool `json:"enableApi,omitempty"`
+	// CustomTerminals are user-defined launchers, offered alongside the
+	// built-in ones.
+	CustomTerminals []TerminalLauncher `json:"customTerminals,omitempty"`
+	// TrustedProjectFiles are the .ya.json files allowed to replace library
+	// shortcuts of the same name, as long as they are unchanged.
+	TrustedProjectFiles []TrustedProjectFile `json:"trustedProjectFiles,omitempty"`
+}
+
+// TrustedProjectFile is a .ya.json the user trusted, with the SHA-256 of
+// the contents they were shown.
+type TrustedProjectFile struct {
+	Path string `json:"path"`
+	Sum  string `json:"sum"`
+}
+
+// ProjectShadow lists the library shortcuts that a .ya.json the user has
+// not trusted, or has changed since, defines as well. Sum identifies the
+// contents the list was made from.
+type ProjectShadow struct {
+	File  string   `json:"file"`
+	Sum   string   `json:"sum"`
+	Names []string `json:"names"`
+}
+
+// TerminalLauncher describes how to open a terminal that runs a command.
+// Args is an argv template whose first element is the program; {shell},
+// {dir} and {command} are replaced within each argument, so nothing needs
+// quoting.
+type TerminalLauncher struct {
+	Name    string   `json:"name"` // what PreferredTerminal refers to
+	Label   string   `json:"label,omitempty"`
+	Args    []string `json:"args"`
+	Shell   string   `json:"shell,omitempty"`   // dialect the command runs in; DefaultShell if empty
+	BuiltIn bool     `json:"builtIn,omitempty"` // set by TerminalLaunchers, never saved
 }
 
 // SavedDir is a named workspace directory preset.
@@ -24,10 +68,20 @@ type SavedDir struct {
 	Path string `json:"path"`
 }
 
-// RunHistoryEntry records one execution of a shortcut.
+// RunHistoryEntry records one execution of a shortcut. ExitCode, DurationMs
+// and OutputTail are only filled in for runs whose process the app owns
+// ("inline" and "pty"), once that process has finished.
 type RunHistoryEntry struct {
-	ShortcutName string `json:"shortcutName"`
-	Command      string `json:"command"`
-	Directory    string `json:"directory"`
-	Timestamp    string `json:"timestamp"`
+	ID           string   `json:"id,omitempty"`
+	ShortcutName string   `json:"shortcutName"`
+	Command      string   `json:"command"`
+	Directory    string   `json:"directory"`
+	Timestamp    string   `json:"timestamp"`
+	Mode         string   `json:"mode,omitempty"`     // "terminal" | "inline" | "pty" | "cli"
+	Source       string   `json:"source,omitempty"`   // project file for shortcuts and tasks outside the library
+	Terminal     string   `json:"terminal,omitempty"` // terminal program used in "terminal" mode
+	ExitCode     *int     `json:"exitCode,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	DurationMs   int64    `json:"durationMs,omitempty"`
+	OutputTail   []string `json:"outputTail,omitempty"`
 }
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goRuntime "runtime"
+	"strings"
+)
+
+// URLScheme is the scheme of links that open Ya GUI, such as
+// yagui://run/build?dir=/home/me/app&var.env=prod.
+const URLScheme = "yagui"
+
+const urlDesktopFile = "yagui-url.desktop"
+
+// ParseRunURL turns a yagui://run/NAME link into a LaunchRequest. The dir
+// query parameter must be an absolute path, and each var.NAME parameter
+// fills in the placeholder NAME. Since anything on the web can hand such a
+// link to the OS, the request is marked with the link so that the user is
+// asked before it goes any further.
+func ParseRunURL(raw string) (req LaunchRequest, err error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return req, fmt.Errorf("invalid link: %w", err)
+	}
+	if u.Scheme != URLScheme {
+		return req, fmt.Errorf("not a %s:// link", URLScheme)
+	}
+	// yagui://run/NAME puts the action in the host; yagui:///run/NAME and
+	// yagui:run/NAME, which some launchers produce, put it in the path.
+	path := u.Path
+	if u.Opaque != "" {
+		if path, err = url.PathUnescape(u.Opaque); err != nil {
+			return req, fmt.Errorf("invalid link: %w", err)
+		}
+	}
+	action, name := u.Host, strings.Trim(path, "/")
+	if action == "" {
+		action, name, _ = strings.Cut(name, "/")
+	}
+	if action != "run" {
+		return req, fmt.Errorf("unsupported link action %q", action)
+	}
+	if name == "" {
+		return req, fmt.Errorf("link does not name a shortcut")
+	}
+
+	req = LaunchRequest{Name: name, URL: raw}
+	for key, values := range u.Query() {
+		value := values[len(values)-1]
+		switch {
+		case key == "dir":
+			if !filepath.IsAbs(value) {
+				return LaunchRequest{}, fmt.Errorf("link directory %q is not an absolute path", value)
+			}
+			req.Dir = filepath.Clean(value)
+		case strings.HasPrefix(key, "var.") && key != "var.":
+			if req.Values == nil {
+				req.Values = map[string]string{}
+			}
+			req.Values[strings.TrimPrefix(key, "var.")] = value
+		}
+	}
+	return req, nil
+}
+
+// ParseLaunchURL is ParseRunURL for a link the OS handed over, keeping a
+// link that cannot be used so the user can be told why.
+func ParseLaunchURL(raw string) LaunchRequest {
+	req, err := ParseRunURL(raw)
+	if err != nil {
+		return LaunchRequest{URL: raw, Error: err.Error()}
+	}
+	return req
+}
+
+// SetURLHandler registers or unregisters Ya GUI as the handler of yagui://
+// links for the current user. On macOS the scheme is declared by the app
+// bundle's Info.plist instead, so it is always registered there.
+func SetURLHandler(enabled bool) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	switch goRuntime.GOOS {
+	case "windows":
+		return setURLHandlerWindows(enabled, exePath)
+	case "darwin":
+		if !enabled {
+			return fmt.Errorf("on macOS %s:// links are registered by the app bundle; remove the app to unregister them", URLScheme)
+		}
+		return nil
+	default:
+		return setURLHandlerLinux(enabled, exePath)
+	}
+}
+
+// GetURLHandler reports whether Ya GUI is registered for yagui:// links.
+func GetURLHandler() bool {
+	switch goRuntime.GOOS {
+	case "windows":
+		return runReg("query", `HKCU\Software\Classes\`+URLScheme+`\shell\open\command`) == nil
+	case "darwin":
+		return true
+	default:
+		path, err := urlDesktopPath()
+		if err != nil {
+			return false
+		}
+		_, err = os.Stat(path)
+		return err == nil
+	}
+}
+
+func setURLHandlerWindows(enabled bool, exePath string) error {
+	key := `HKCU\Software\Classes\` + URLScheme
+	if !enabled {
+		return runReg("delete", key, "/f")
+	}
+	if err := runReg("add", key, "/ve", "/d", "URL:YaGUI", "/f"); err != nil {
+		return err
+	}
+	if err := runReg("add", key, "/v", "URL Protocol", "/d", "", "/f"); err != nil {
+		return err
+	}
+	return runReg("add", key+`\shell\open\command`, "/ve", "/d", `"`+exePath+`" "%1"`, "/f")
+}
+
+func setURLHandlerLinux(enabled bool, exePath string) error {
+	desktop, err := urlDesktopPath()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return os.Remove(desktop)
+	}
+	mime := "x-scheme-handler/" + URLScheme
+	keys := fmt.Sprintf("Exec=%s %%u\nNoDisplay=true\nMimeType=%s;\n", desktopQuote(exePath), mime)
+	if err := writeDesktopEntry(desktop, keys); err != nil {
+		return err
+	}
+	// Refreshing the cache is best effort; xdg-mime is what makes the
+	// desktop hand yagui:// links to us.
+	_ = exec.Command("update-desktop-database", filepath.Dir(desktop)).Run()
+	if out, err := exec.Command("xdg-mime", "default", urlDesktopFile, mime).CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("xdg-mime: %s", msg)
+		}
+		return fmt.Errorf("xdg-mime: %w", err)
+	}
+	return nil
+}
+
+// urlDesktopPath is where the yagui:// handler's .desktop file lives.
+func urlDesktopPath() (string, error) {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataDir, "applications", urlDesktopFile), nil
+}
+
+// desktopQuote quotes an Exec argument as the Desktop Entry spec requires.
+// Backslashes are unescaped once for the string value and once more for
+// the quoting, hence the doubling.
+func desktopQuote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`, "`", "\\\\`", `$`, `\\$`, `%`, `%%`).Replace(arg) + `"`
+}
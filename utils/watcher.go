@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce coalesces the bursts of events a single save produces.
+const watchDebounce = 250 * time.Millisecond
+
+// watchedFiles maps the data files to the frontend event announcing them.
+var watchedFiles = map[string]string{
+	"shortcuts.json":      "shortcuts:changed",
+	"shortcuts-meta.json": "shortcuts:changed",
+	"config.json":         "config:changed",
+	"history.json":        "history:changed",
+}
+
+// knownSums holds the last content hash of each data file that the app
+// either wrote itself or already reported, so only genuinely new content
+// (e.g. `ya add` in a terminal) triggers a reload.
+var (
+	knownMu   sync.Mutex
+	knownSums = map[string]string{}
+)
+
+// noteFileContent records data as the known content of path.
+func noteFileContent(path string, data []byte) {
+	knownMu.Lock()
+	knownSums[path] = dataSum(data)
+	knownMu.Unlock()
+}
+
+// contentChanged reports whether path differs from its known content and
+// remembers the new content if so. A missing file hashes as "".
+func contentChanged(path string) bool {
+	sum := ""
+	if data, err := os.ReadFile(path); err == nil {
+		sum = dataSum(data)
+	}
+	knownMu.Lock()
+	defer knownMu.Unlock()
+	if old, ok := knownSums[path]; ok && old == sum {
+		return false
+	}
+	knownSums[path] = sum
+	return true
+}
+
+// DataWatcher reports changes other programs make to the data directory.
+type DataWatcher struct {
+	w    *fsnotify.Watcher
+	done chan struct{}
+}
+
+// WatchDataDir starts watching the data directory and calls onChange with
+// the event name ("shortcuts:changed", "config:changed" or
+// "history:changed") after an external change settles.
+func WatchDataDir(onChange func(event string)) (*DataWatcher, error) {
+	dir, err := getAppDataDir()
+	if err != nil {
+		return nil, err
+	}
+	// Seed the known state so the first external edit is compared against
+	// what the frontend loaded, not against nothing.
+	for name := range watchedFiles {
+		contentChanged(filepath.Join(dir, name))
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// Watch the directory rather than the files: atomic saves replace the
+	// file, which would silently end a per-file watch.
+	if err := w.Add(dir); err != nil {
+		w.Close()
+		return nil, err
+	}
+	dw := &DataWatcher{w: w, done: make(chan struct{})}
+	go dw.loop(onChange)
+	return dw, nil
+}
+
+func (dw *DataWatcher) loop(onChange func(event string)) {
+	defer close(dw.done)
+	var mu sync.Mutex
+	timers := map[string]*time.Timer{}
+	defer func() {
+		mu.Lock()
+		for _, t := range timers {
+			t.Stop()
+		}
+		mu.Unlock()
+	}()
+
+	for {
+		select {
+		case ev, ok := <-dw.w.Events:
+			if !ok {
+				return
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			if _, ok := watchedFiles[filepath.Base(ev.Name)]; !ok {
+				continue
+			}
+			path := ev.Name
+			mu.Lock()
+			if t, ok := timers[path]; ok {
+				t.Reset(watchDebounce)
+			} else {
+				timers[path] = time.AfterFunc(watchDebounce, func() {
+					mu.Lock()
+					delete(timers, path)
+					mu.Unlock()
+					if contentChanged(path) {
+						onChange(watchedFiles[filepath.Base(path)])
+					}
+				})
+			}
+			mu.Unlock()
+		case _, ok := <-dw.w.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// Close stops the watcher.
+func (dw *DataWatcher) Close() error {
+	err := dw.w.Close()
+	<-dw.done
+	return err
+}